@@ -1,28 +1,128 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 
+	"github.com/Drack112/go-youtube/internal/api"
 	"github.com/Drack112/go-youtube/internal/flags"
+	"github.com/Drack112/go-youtube/internal/handlers"
+	"github.com/Drack112/go-youtube/internal/player"
 	"github.com/Drack112/go-youtube/internal/tui"
+	"github.com/Drack112/go-youtube/pkg/httpx"
 	"github.com/Drack112/go-youtube/pkg/logger"
+	"github.com/Drack112/go-youtube/pkg/utils"
 )
 
 func main() {
+	if flags.IsCaptionsCommand() {
+		runCaptions()
+		return
+	}
+
 	opts, err := flags.ParseFlags()
 	if err != nil {
 		switch err {
 		case flags.ErrHelpRequested:
 			return
 		default:
-			logger.Fatal(flags.ErrorHandler(err))
+			logger.Error(flags.ErrorHandler(err))
+			fmt.Printf("Error: %v\n", flags.ErrorHandler(err))
+			os.Exit(1)
 		}
 	}
 
-	model := tui.NewModel(opts)
+	client, err := newClient(opts.NetworkOptions)
+	if err != nil {
+		fmt.Printf("Error: %v\n", flags.ErrorHandler(err))
+		os.Exit(1)
+	}
+
+	client.SetLocale(opts.Language, opts.Region)
+
+	provider, err := api.NewProvider(opts.Backend, opts.Instances, client)
+	if err != nil {
+		fmt.Printf("Error: %v\n", flags.ErrorHandler(err))
+		os.Exit(1)
+	}
+
+	model := tui.NewModel(opts, provider)
 	if err := tui.NewProgram(model).Start(); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+func runCaptions() {
+	opts, err := flags.ParseCaptionsFlags(os.Args[2:])
+	if err != nil {
+		if err == flags.ErrHelpRequested {
+			return
+		}
+		fmt.Printf("Error: %v\n", flags.ErrorHandler(err))
+		os.Exit(1)
+	}
+
+	client, err := newClient(opts.NetworkOptions)
+	if err != nil {
+		fmt.Printf("Error: %v\n", flags.ErrorHandler(err))
+		os.Exit(1)
+	}
+
+	client.SetLocale(opts.Language, opts.Region)
+
+	provider, err := api.NewProvider(opts.Backend, opts.Instances, client)
+	if err != nil {
+		fmt.Printf("Error: %v\n", flags.ErrorHandler(err))
+		os.Exit(1)
+	}
+
+	// ctrl+c abandons the request in flight instead of waiting for it
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := handlers.RunCaptions(ctx, provider, opts); err != nil {
+		fmt.Printf("Error: %v\n", flags.ErrorHandler(err))
+		os.Exit(1)
+	}
+}
+
+// newClient creates the api client with the network flags applied, and hands the same proxy, headers
+// and cookies to utils.Fetch and to mpv/yt-dlp
+func newClient(network flags.NetworkOptions) (*api.Client, error) {
+	httpOpts, err := network.HTTPOptions()
+	if err != nil {
+		return nil, err
+	}
+	headers := network.RequestHeaders()
+
+	client := api.NewClient()
+	client.HTTPClient = httpx.NewClient(httpOpts)
+	client.Timeout = network.Timeout
+	switch innertube, ok := api.InnertubeClients[network.Client]; {
+	case network.Client == "html":
+		client.Scrape = true
+	case ok:
+		client.PlayerClient = innertube
+	case network.Client != "":
+		return nil, fmt.Errorf("unknown -client %q, want web, mweb, android, tv or html", network.Client)
+	}
+	for key, values := range headers {
+		client.Headers[key] = values
+	}
+
+	utils.DefaultClient = client.HTTPClient
+	utils.DefaultHeaders = headers
+
+	player.Network = player.NetworkConfig{
+		Headers:    headers,
+		CookieFile: network.Cookies,
+	}
+	if httpOpts.Proxy != nil {
+		player.Network.Proxy = httpOpts.Proxy.String()
+	}
+
+	return client, nil
+}
@@ -0,0 +1,275 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Drack112/go-youtube/internal/models"
+)
+
+// CaptionFormats lists the output formats supported by FormatCaptions
+var CaptionFormats = []string{"srt", "vtt", "txt"}
+
+func parseCaptionTracks(player *playerResponse) []models.CaptionTrack {
+	var tracks []models.CaptionTrack
+	for _, t := range player.Captions.PlayerCaptionsTracklistRenderer.CaptionTracks {
+		baseURL := t.BaseURL
+		if baseURL == "" {
+			continue
+		}
+		if strings.HasPrefix(baseURL, "/") {
+			baseURL = "https://www.youtube.com" + baseURL
+		}
+
+		tracks = append(tracks, models.CaptionTrack{
+			LanguageCode:  t.LanguageCode,
+			LanguageName:  t.Name.String(),
+			URL:           baseURL,
+			AutoGenerated: t.Kind == "asr",
+		})
+	}
+
+	return tracks
+}
+
+// FindCaptionTrack picks the track for lang, preferring manual captions over auto-generated ones
+func FindCaptionTrack(tracks []models.CaptionTrack, lang string) (models.CaptionTrack, bool) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+
+	var auto *models.CaptionTrack
+	for i, t := range tracks {
+		code := strings.ToLower(t.LanguageCode)
+		if code != lang && !strings.HasPrefix(code, lang+"-") {
+			continue
+		}
+		if !t.AutoGenerated {
+			return t, true
+		}
+		if auto == nil {
+			auto = &tracks[i]
+		}
+	}
+
+	if auto != nil {
+		return *auto, true
+	}
+	return models.CaptionTrack{}, false
+}
+
+// FetchCaptionCues downloads a caption track and decodes it into timed cues
+func (c *Client) FetchCaptionCues(ctx context.Context, track models.CaptionTrack) ([]models.CaptionCue, error) {
+	if track.URL == "" {
+		return nil, errors.New("caption track has no URL")
+	}
+
+	// the baseUrl is signed, so it is requested as given, without the hl/gl and consent handling of fetch.
+	// Only the format is added when the URL names none, decodeCaptions reads whichever one comes back.
+	u, err := url.Parse(c.resolve(track.URL))
+	if err != nil {
+		return nil, err
+	}
+	if !u.Query().Has("fmt") {
+		u.RawQuery = strings.TrimPrefix(u.RawQuery+"&fmt=json3", "&")
+	}
+
+	c.log().Debug("[FetchCaptionCues] fetching timedtext", "lang", track.LanguageCode)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.do(req)
+	if err != nil {
+		c.log().Error("[FetchCaptionCues] fetch failed", "error", err)
+		return nil, err
+	}
+
+	return decodeCaptions(string(body))
+}
+
+// decodeCaptions tells the caption formats apart by their first bytes
+func decodeCaptions(body string) ([]models.CaptionCue, error) {
+	body = strings.TrimSpace(body)
+	switch {
+	case body == "":
+		return nil, errors.New("empty caption response")
+	case strings.HasPrefix(body, "WEBVTT"):
+		return parseWebVTT(body), nil
+	case strings.HasPrefix(body, "<"):
+		// some tracks ignore fmt=json3 and still answer with timedtext XML
+		return parseTimedTextXML([]byte(body))
+	}
+	return parseTimedTextJSON3([]byte(body))
+}
+
+// parseWebVTT reads the cues of a WebVTT file, the format Invidious and Piped serve captions in
+func parseWebVTT(body string) []models.CaptionCue {
+	var cues []models.CaptionCue
+	for _, block := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n\n") {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		for i, line := range lines {
+			start, end, ok := strings.Cut(line, " --> ")
+			if !ok {
+				continue
+			}
+			// cue settings such as "align:start position:0%" follow the end time
+			end, _, _ = strings.Cut(strings.TrimSpace(end), " ")
+
+			text := cleanCaptionText(strings.Join(lines[i+1:], "\n"))
+			if text != "" {
+				cues = append(cues, models.CaptionCue{StartMs: vttTimestamp(start), EndMs: vttTimestamp(end), Text: text})
+			}
+			break
+		}
+	}
+	return cues
+}
+
+// vttTimestamp parses "01:02:03.456" or "02:03.456" into milliseconds
+func vttTimestamp(ts string) int {
+	ts = strings.TrimSpace(ts)
+	secs, frac, _ := strings.Cut(ts, ".")
+	ms, _ := strconv.Atoi((frac + "000")[:3])
+
+	total := 0
+	for _, part := range strings.Split(secs, ":") {
+		n, _ := strconv.Atoi(part)
+		total = total*60 + n
+	}
+	return total*1000 + ms
+}
+
+func parseTimedTextJSON3(data []byte) ([]models.CaptionCue, error) {
+	var doc struct {
+		Events []struct {
+			TStartMs    int `json:"tStartMs"`
+			DDurationMs int `json:"dDurationMs"`
+			Segs        []struct {
+				UTF8 string `json:"utf8"`
+			} `json:"segs"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid json3 captions: %w", err)
+	}
+
+	var cues []models.CaptionCue
+	for _, ev := range doc.Events {
+		var text strings.Builder
+		for _, seg := range ev.Segs {
+			text.WriteString(seg.UTF8)
+		}
+
+		t := strings.TrimSpace(text.String())
+		if t == "" {
+			continue
+		}
+		cues = append(cues, models.CaptionCue{
+			StartMs: ev.TStartMs,
+			EndMs:   ev.TStartMs + ev.DDurationMs,
+			Text:    t,
+		})
+	}
+
+	return cues, nil
+}
+
+// parseTimedTextXML handles both the legacy <transcript><text start dur> format and srv3 <timedtext><body><p t d>
+func parseTimedTextXML(data []byte) ([]models.CaptionCue, error) {
+	type textNode struct {
+		Start string `xml:"start,attr"`
+		Dur   string `xml:"dur,attr"`
+		T     string `xml:"t,attr"`
+		D     string `xml:"d,attr"`
+		Inner string `xml:",innerxml"`
+	}
+	var doc struct {
+		Texts []textNode `xml:"text"`
+		Body  struct {
+			Paragraphs []textNode `xml:"p"`
+		} `xml:"body"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid timedtext captions: %w", err)
+	}
+
+	var cues []models.CaptionCue
+	for _, n := range doc.Texts {
+		start, _ := strconv.ParseFloat(n.Start, 64)
+		dur, _ := strconv.ParseFloat(n.Dur, 64)
+		if text := cleanCaptionText(n.Inner); text != "" {
+			cues = append(cues, models.CaptionCue{
+				StartMs: int(start * 1000),
+				EndMs:   int((start + dur) * 1000),
+				Text:    text,
+			})
+		}
+	}
+	for _, p := range doc.Body.Paragraphs {
+		start, _ := strconv.Atoi(p.T)
+		dur, _ := strconv.Atoi(p.D)
+		if text := cleanCaptionText(p.Inner); text != "" {
+			cues = append(cues, models.CaptionCue{StartMs: start, EndMs: start + dur, Text: text})
+		}
+	}
+
+	return cues, nil
+}
+
+// cleanCaptionText strips inline tags (<s>, <font>) and decodes entities, which are often double-escaped
+func cleanCaptionText(raw string) string {
+	var b strings.Builder
+	inTag := false
+	for _, r := range raw {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimSpace(html.UnescapeString(html.UnescapeString(b.String())))
+}
+
+// FormatCaptions renders cues as "srt", "vtt" or plain "txt"
+func FormatCaptions(cues []models.CaptionCue, format string) (string, error) {
+	var b strings.Builder
+
+	switch strings.ToLower(format) {
+	case "srt":
+		for i, c := range cues {
+			fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, captionTimestamp(c.StartMs, ","), captionTimestamp(c.EndMs, ","), c.Text)
+		}
+	case "vtt":
+		b.WriteString("WEBVTT\n\n")
+		for _, c := range cues {
+			fmt.Fprintf(&b, "%s --> %s\n%s\n\n", captionTimestamp(c.StartMs, "."), captionTimestamp(c.EndMs, "."), c.Text)
+		}
+	case "txt", "text":
+		for _, c := range cues {
+			b.WriteString(c.Text)
+			b.WriteString("\n")
+		}
+	default:
+		return "", fmt.Errorf("unsupported caption format %q (use srt, vtt or txt)", format)
+	}
+
+	return b.String(), nil
+}
+
+func captionTimestamp(ms int, sep string) string {
+	h := ms / 3600000
+	m := (ms % 3600000) / 60000
+	s := (ms % 60000) / 1000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, sep, ms%1000)
+}
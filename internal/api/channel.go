@@ -0,0 +1,306 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+const youtubeBase = "https://www.youtube.com"
+
+type ChannelTab string
+
+const (
+	ChannelTabVideos    ChannelTab = "videos"
+	ChannelTabShorts    ChannelTab = "shorts"
+	ChannelTabLive      ChannelTab = "streams"
+	ChannelTabPlaylists ChannelTab = "playlists"
+)
+
+// ChannelTabs is the order in which the TUI cycles through tabs
+var ChannelTabs = []ChannelTab{ChannelTabVideos, ChannelTabShorts, ChannelTabLive, ChannelTabPlaylists}
+
+func (t ChannelTab) Label() string {
+	switch t {
+	case ChannelTabShorts:
+		return "Shorts"
+	case ChannelTabLive:
+		return "Live"
+	case ChannelTabPlaylists:
+		return "Playlists"
+	default:
+		return "Videos"
+	}
+}
+
+type ChannelResponse struct {
+	Channel           *models.ChannelInfo // only filled on the first page
+	Tab               ChannelTab
+	Results           []models.SearchResult
+	ContinuationToken string
+	HasMore           bool
+}
+
+// GetChannel resolves a channel URL, @handle or UC id and lists one of its tabs.
+// Pass the token of a previous response to fetch the next page of that tab.
+func (c *Client) GetChannel(ctx context.Context, ref string, tab ChannelTab, continuationToken string) (*ChannelResponse, error) {
+	if tab == "" {
+		tab = ChannelTabVideos
+	}
+
+	if continuationToken != "" {
+		resp, err := c.channelContinuation(ctx, tab, continuationToken)
+		// the items of later pages omit the owner as well
+		if channel := c.seenChannel(ref); err == nil && channel != nil {
+			fillChannel(resp.Results, channel)
+		}
+		return resp, err
+	}
+
+	path := utils.ChannelPath(ref)
+	if path == "" {
+		return nil, fmt.Errorf("invalid channel reference: %s", ref)
+	}
+
+	resp, err := c.channelFirstPage(ctx, path, tab)
+	if err != nil {
+		return nil, err
+	}
+	c.channels.Store(path, resp.Channel)
+	c.channels.Store("/channel/"+resp.Channel.ID, resp.Channel)
+	return resp, nil
+}
+
+func (c *Client) channelFirstPage(ctx context.Context, path string, tab ChannelTab) (*ChannelResponse, error) {
+	if !c.Scrape {
+		resp, err := c.channelInnertube(ctx, path, tab)
+		if err == nil || !scrapeAfter(ctx, err) {
+			return resp, err
+		}
+		c.log().Warn("[GetChannel] innertube browse failed, reading the channel page", "path", path, "error", err)
+	}
+	return c.channelHTML(ctx, path, tab)
+}
+
+// seenChannel returns the channel a first page of ref was served for, or one with just the id when ref
+// is a UC id that was not seen yet
+func (c *Client) seenChannel(ref string) *models.ChannelInfo {
+	path := utils.ChannelPath(ref)
+	if channel, ok := c.channels.Load(path); ok {
+		return channel.(*models.ChannelInfo)
+	}
+	if id, ok := strings.CutPrefix(path, "/channel/"); ok {
+		return &models.ChannelInfo{ID: id}
+	}
+	return nil
+}
+
+// channelInnertube browses the UC id behind path with the params of tab
+func (c *Client) channelInnertube(ctx context.Context, path string, tab ChannelTab) (*ChannelResponse, error) {
+	params, ok := channelTabParams[tab]
+	if !ok {
+		return nil, fmt.Errorf("unknown channel tab: %s", tab)
+	}
+
+	id, err := c.channelBrowseID(ctx, path)
+	if err != nil {
+		c.log().Error("[channelInnertube] failed to resolve channel", "path", path, "error", err)
+		return nil, err
+	}
+
+	c.log().Debug("[channelInnertube] browsing channel tab", "id", id, "tab", tab)
+
+	body, err := c.postInnertube(ctx, InnertubeWeb, "browse", map[string]any{"browseId": id, "params": params})
+	if err != nil {
+		c.log().Error("[channelInnertube] request failed", "error", err)
+		return nil, err
+	}
+
+	var page browsePage
+	if err := c.decode(body, &page); err != nil {
+		c.log().Error("[channelInnertube] failed to decode response", "error", err)
+		return nil, err
+	}
+	return channelResponse(&page, tab)
+}
+
+func (c *Client) channelHTML(ctx context.Context, path string, tab ChannelTab) (*ChannelResponse, error) {
+	c.log().Debug("[channelHTML] fetching channel tab", "path", path, "tab", tab)
+
+	body, err := c.fetch(ctx, path+"/"+string(tab))
+	if err != nil {
+		c.log().Error("[channelHTML] fetch failed", "error", err)
+		return nil, err
+	}
+
+	jsonData, err := extractInitialData(body)
+	if err != nil {
+		c.log().Error("[channelHTML] failed to extract ytInitialData", "error", err)
+		return nil, pageError(body, err)
+	}
+
+	var page browsePage
+	if err := c.decode(jsonData, &page); err != nil {
+		c.log().Error("[channelHTML] failed to decode ytInitialData", "error", err)
+		return nil, err
+	}
+	return channelResponse(&page, tab)
+}
+
+func channelResponse(page *browsePage, tab ChannelTab) (*ChannelResponse, error) {
+	// stale browse params, and a tab the channel does not have, land on the home tab instead of failing
+	if selected := page.selectedTab(); selected == nil || !strings.HasSuffix(selected.Endpoint.CommandMetadata.WebCommandMetadata.URL, "/"+string(tab)) {
+		return nil, layoutError(fmt.Errorf("channel page did not select the %s tab", tab))
+	}
+
+	channel := parseChannelInfo(page)
+	if channel.ID == "" {
+		return nil, layoutError(errors.New("channel metadata missing"))
+	}
+
+	contents := selectedTabContents(page)
+	results := parseItems(contents)
+	fillChannel(results, channel)
+	continuation := findContinuationToken(contents)
+
+	return &ChannelResponse{
+		Channel:           channel,
+		Tab:               tab,
+		Results:           results,
+		ContinuationToken: continuation,
+		HasMore:           continuation != "",
+	}, nil
+}
+
+func (c *Client) channelContinuation(ctx context.Context, tab ChannelTab, token string) (*ChannelResponse, error) {
+	c.log().Debug("[channelContinuation] fetching next page", "tab", tab)
+
+	body, err := c.postInnertube(ctx, InnertubeWeb, "browse", map[string]any{
+		"continuation": token,
+	})
+	if err != nil {
+		c.log().Error("[channelContinuation] request failed", "error", err)
+		return nil, err
+	}
+
+	var resp continuationResponse
+	if err := c.decode(body, &resp); err != nil {
+		c.log().Error("[channelContinuation] failed to decode response", "error", err)
+		return nil, err
+	}
+
+	items, err := continuationItems(&resp)
+	if err != nil {
+		c.log().Error("[channelContinuation] failed to parse results", "error", err)
+		return nil, err
+	}
+
+	continuation := findContinuationToken(items)
+	return &ChannelResponse{
+		Tab:               tab,
+		Results:           parseItems(items),
+		ContinuationToken: continuation,
+		HasMore:           continuation != "",
+	}, nil
+}
+
+func parseChannelInfo(page *browsePage) *models.ChannelInfo {
+	channel := &models.ChannelInfo{}
+
+	if meta := page.Metadata.ChannelMetadataRenderer; meta != nil {
+		channel.ID = meta.ExternalID
+		channel.Name = meta.Title
+		channel.Description = meta.Description
+		channel.URL = meta.VanityChannelURL
+		channel.Thumbnail = meta.Avatar.largest()
+	}
+
+	if channel.URL == "" && channel.ID != "" {
+		channel.URL = youtubeBase + "/channel/" + channel.ID
+	}
+	if i := strings.Index(channel.URL, "/@"); i >= 0 {
+		channel.Handle = channel.URL[i+1:]
+	}
+
+	// classic c4TabbedHeaderRenderer layout
+	if c4 := page.Header.C4TabbedHeaderRenderer; c4 != nil {
+		channel.SubscriberCount = utils.ParseCount(c4.SubscriberCountText.String())
+		channel.Verified, channel.OfficialArtist = ownerFlags(c4.Badges)
+		if channel.Handle == "" {
+			channel.Handle = c4.ChannelHandleText.String()
+		}
+		return channel
+	}
+	if page.Header.PageHeaderRenderer == nil {
+		return channel
+	}
+
+	// pageHeaderRenderer layout: handle and counts live in metadata rows, badges are icons on the title
+	for _, part := range page.Header.metadataParts() {
+		text := part.Text.Content
+		switch {
+		case strings.HasPrefix(text, "@") && channel.Handle == "":
+			channel.Handle = text
+		case utils.IsSubscriberCount(text):
+			channel.SubscriberCount = utils.ParseCount(text)
+		}
+	}
+
+	for _, name := range page.Header.PageHeaderRenderer.Content.PageHeaderViewModel.Title.DynamicTextViewModel.Text.imageNames() {
+		switch name {
+		case "CHECK_CIRCLE_FILLED", "CHECK_CIRCLE_THICK":
+			channel.Verified = true
+		case "AUDIO_BADGE", "MUSIC_FILLED":
+			channel.Verified = true
+			channel.OfficialArtist = true
+		}
+	}
+
+	return channel
+}
+
+// selectedTabContents returns the item list of the tab the page was opened on
+func selectedTabContents(page *browsePage) []contentItem {
+	tab := page.selectedTab()
+	if tab == nil {
+		return nil
+	}
+
+	if grid := tab.Content.RichGridRenderer; grid != nil {
+		return grid.Contents
+	}
+	if tab.Content.SectionListRenderer == nil {
+		return nil
+	}
+
+	// older tabs wrap a single grid in a section, its items carry the continuation
+	section := tab.Content.SectionListRenderer.Contents
+	for _, item := range section {
+		if item.ItemSectionRenderer == nil {
+			continue
+		}
+		for _, inner := range item.ItemSectionRenderer.Contents {
+			if inner.GridRenderer != nil {
+				return inner.GridRenderer.Items
+			}
+		}
+	}
+	return section
+}
+
+// fillChannel sets the channel on results whose renderer omits the owner (channel pages never repeat it)
+func fillChannel(results []models.SearchResult, channel *models.ChannelInfo) {
+	for i := range results {
+		if results[i].ChannelName == "" {
+			results[i].ChannelName = channel.Name
+		}
+		if results[i].ChannelID == "" {
+			results[i].ChannelID = channel.ID
+			results[i].ChannelURl = youtubeBase + "/channel/" + channel.ID
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetChannel(t *testing.T) {
+	c := newTestClient(t)
+
+	resp, err := c.GetChannel(t.Context(), fixtureChannel, ChannelTabVideos, "")
+	if err != nil {
+		t.Fatalf("GetChannel: %v", err)
+	}
+	if resp.Channel == nil || resp.Channel.ID == "" || resp.Channel.Handle == "" {
+		t.Fatalf("channel metadata missing: %+v", resp.Channel)
+	}
+	if len(resp.Results) == 0 {
+		t.Fatal("no videos parsed from the channel page")
+	}
+	for _, r := range resp.Results {
+		if r.ChannelID != resp.Channel.ID {
+			t.Errorf("video %s not attributed to the channel", r.ID)
+		}
+	}
+	assertGolden(t, "channel_videos", resp)
+
+	if !resp.HasMore {
+		t.Fatal("first channel page should carry a continuation token")
+	}
+	next, err := c.GetChannel(t.Context(), resp.Channel.ID, ChannelTabVideos, resp.ContinuationToken)
+	if err != nil {
+		t.Fatalf("continuation: %v", err)
+	}
+	for _, r := range next.Results {
+		if r.ChannelID != resp.Channel.ID || r.ChannelName != resp.Channel.Name {
+			t.Errorf("video %s of the next page not attributed to the channel", r.ID)
+		}
+	}
+	assertGolden(t, "channel_videos_continuation", next)
+}
+
+func TestGetChannelScrapeContinuation(t *testing.T) {
+	c := newTestClient(t)
+	c.Scrape = true
+
+	resp, err := c.GetChannel(t.Context(), fixtureChannel, ChannelTabVideos, "")
+	if err != nil {
+		t.Fatalf("GetChannel: %v", err)
+	}
+	next, err := c.GetChannel(t.Context(), fixtureChannel, ChannelTabVideos, resp.ContinuationToken)
+	if err != nil {
+		t.Fatalf("continuation: %v", err)
+	}
+	if len(next.Results) == 0 {
+		t.Fatal("no videos on the next page")
+	}
+	for _, r := range next.Results {
+		if r.ChannelID != resp.Channel.ID || r.ChannelName != resp.Channel.Name {
+			t.Errorf("video %s of the next page not attributed to the channel", r.ID)
+		}
+	}
+}
+
+func TestGetChannelScrapeMissingTab(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join(fixtureDir, "GoogleDevelopers_videos.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a channel without the tab answers with another one, here the videos tab
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(page)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newRESTClient(srv)
+	c.BaseURL = srv.URL
+	c.Scrape = true
+
+	resp, err := c.GetChannel(t.Context(), fixtureChannel, ChannelTabShorts, "")
+	if !errors.Is(err, ErrLayoutChanged) {
+		t.Fatalf("want ErrLayoutChanged for a page showing another tab, got %v (%+v)", err, resp)
+	}
+}
+
+func TestGetChannelPlaylists(t *testing.T) {
+	c := newTestClient(t)
+
+	resp, err := c.GetChannel(t.Context(), fixtureChannel, ChannelTabPlaylists, "")
+	if err != nil {
+		t.Fatalf("GetChannel: %v", err)
+	}
+	if !resp.Channel.Verified {
+		t.Error("verified badge of the page header not detected")
+	}
+	assertGolden(t, "channel_playlists", resp)
+}
+
+func TestGetChannelShorts(t *testing.T) {
+	c := newTestClient(t)
+
+	resp, err := c.GetChannel(t.Context(), fixtureChannel, ChannelTabShorts, "")
+	if err != nil {
+		t.Fatalf("GetChannel: %v", err)
+	}
+	if len(resp.Results) == 0 {
+		t.Fatal("no shorts parsed from the channel page")
+	}
+	for _, r := range resp.Results {
+		if !r.IsShort || r.Title == "" || r.ViewCount == 0 {
+			t.Errorf("incomplete short: %+v", r)
+		}
+		if r.ChannelID != resp.Channel.ID {
+			t.Errorf("short %s not attributed to the channel", r.ID)
+		}
+	}
+	assertGolden(t, "channel_shorts", resp)
+}
+
+func TestGetChannelLive(t *testing.T) {
+	c := newTestClient(t)
+
+	resp, err := c.GetChannel(t.Context(), fixtureChannel, ChannelTabLive, "")
+	if err != nil {
+		t.Fatalf("GetChannel: %v", err)
+	}
+	live := 0
+	for _, r := range resp.Results {
+		if r.IsLive {
+			live++
+		} else if r.DurationSec == 0 {
+			t.Errorf("past stream %s lacks its duration", r.ID)
+		}
+	}
+	if live != 1 {
+		t.Errorf("%d streams live now, want 1", live)
+	}
+	assertGolden(t, "channel_live", resp)
+}
+
+func TestGetChannelFeed(t *testing.T) {
+	c := newTestClient(t)
+
+	results, err := c.GetChannelFeed(t.Context(), fixtureChannel)
+	if err != nil {
+		t.Fatalf("GetChannelFeed: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("no entries parsed from the feed")
+	}
+	for i, r := range results {
+		if r.PublishedAt.IsZero() || r.ViewCount == 0 || r.ChannelID == "" {
+			t.Errorf("entry %s lacks its publish time, views or channel", r.ID)
+		}
+		if age := time.Since(r.PublishedAt); r.PublishedAge > age || r.PublishedAge < age-time.Minute {
+			t.Errorf("entry %s is %v old, feed says %v", r.ID, r.PublishedAge, age)
+		}
+		// the age depends on when the test runs
+		results[i].PublishedAge = 0
+	}
+	assertGolden(t, "channel_feed", results)
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// description lines such as "00:00 Intro", "[1:02:03] - Q&A" or "Intro (12:34)"
+var (
+	leadingChapterRegex  = regexp.MustCompile(`^\s*[\[(]?((?:\d{1,2}:)?\d{1,2}:\d{2})[\])]?[\s\-–|:.]*(.+)$`)
+	trailingChapterRegex = regexp.MustCompile(`^\s*(.+?)[\s\-–|:]*[\[(]?((?:\d{1,2}:)?\d{1,2}:\d{2})[\])]?\s*$`)
+)
+
+func parseChapters(next *watchNext, description string) []models.Chapter {
+	if next != nil {
+		if chapters := parseMarkerChapters(next); len(chapters) > 0 {
+			return chapters
+		}
+		if chapters := parseMacroMarkerChapters(next); len(chapters) > 0 {
+			return chapters
+		}
+	}
+
+	return parseDescriptionChapters(description)
+}
+
+// parseMarkerChapters reads the chapterRenderer entries of the player bar overlay
+func parseMarkerChapters(next *watchNext) []models.Chapter {
+	var chapters []models.Chapter
+
+	bar := next.PlayerOverlays.PlayerOverlayRenderer.DecoratedPlayerBarRenderer.DecoratedPlayerBarRenderer.PlayerBar
+	for _, markers := range bar.MultiMarkersPlayerBarRenderer.MarkersMap {
+		for _, c := range markers.Value.Chapters {
+			cr := c.ChapterRenderer
+			if cr == nil || cr.TimeRangeStartMillis == nil {
+				continue
+			}
+			chapters = append(chapters, models.Chapter{
+				Title:     cr.Title.String(),
+				StartSec:  *cr.TimeRangeStartMillis / 1000,
+				Thumbnail: cr.Thumbnail.largest(),
+			})
+		}
+	}
+
+	return normalizeChapters(chapters)
+}
+
+// parseMacroMarkerChapters reads the chapter list of the "In this video" engagement panel
+func parseMacroMarkerChapters(next *watchNext) []models.Chapter {
+	var chapters []models.Chapter
+
+	for _, panel := range next.EngagementPanels {
+		list := panel.EngagementPanelSectionListRenderer.Content.MacroMarkersListRenderer
+		if list == nil {
+			continue
+		}
+		for _, c := range list.Contents {
+			marker := c.MacroMarkersListItemRenderer
+			if marker == nil {
+				continue
+			}
+
+			start := -1
+			if sec := marker.OnTap.WatchEndpoint.StartTimeSeconds; sec != nil {
+				start = *sec
+			} else if ts := marker.TimeDescription.String(); ts != "" {
+				start = utils.ParseDuration(ts)
+			}
+			if start < 0 {
+				continue
+			}
+
+			chapters = append(chapters, models.Chapter{
+				Title:     marker.Title.String(),
+				StartSec:  start,
+				Thumbnail: marker.Thumbnail.largest(),
+			})
+		}
+	}
+
+	return normalizeChapters(chapters)
+}
+
+// parseDescriptionChapters follows YouTube's own rule: at least two timestamps and the first one at 0:00
+func parseDescriptionChapters(description string) []models.Chapter {
+	var chapters []models.Chapter
+
+	for _, line := range strings.Split(description, "\n") {
+		var timestamp, title string
+		if match := leadingChapterRegex.FindStringSubmatch(line); match != nil {
+			timestamp, title = match[1], match[2]
+		} else if match := trailingChapterRegex.FindStringSubmatch(line); match != nil {
+			timestamp, title = match[2], match[1]
+		} else {
+			continue
+		}
+
+		title = strings.Trim(strings.TrimSpace(title), " -–|:()[]")
+		if title == "" {
+			continue
+		}
+
+		chapters = append(chapters, models.Chapter{
+			Title:    title,
+			StartSec: utils.ParseDuration(timestamp),
+		})
+	}
+
+	chapters = normalizeChapters(chapters)
+	if len(chapters) < 2 || chapters[0].StartSec != 0 {
+		return nil
+	}
+	return chapters
+}
+
+// normalizeChapters sorts by start time and drops duplicated offsets, which appear when several layouts overlap
+func normalizeChapters(chapters []models.Chapter) []models.Chapter {
+	if len(chapters) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].StartSec < chapters[j].StartSec
+	})
+
+	out := chapters[:0]
+	for i, c := range chapters {
+		if i > 0 && c.StartSec == out[len(out)-1].StartSec {
+			continue
+		}
+		if c.Title == "" {
+			c.Title = "Chapter " + strconv.Itoa(len(out)+1)
+		}
+		out = append(out, c)
+	}
+
+	return out
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Drack112/go-youtube/pkg/httpx"
+	"github.com/Drack112/go-youtube/pkg/logger"
+	"github.com/Drack112/go-youtube/pkg/utils"
+	clog "github.com/charmbracelet/log"
+)
+
+// Client talks to YouTube. Every field can be changed after NewClient, e.g. to point BaseURL at a
+// mirror or a local test server. URLs handed back in results always use the canonical youtube.com host.
+type Client struct {
+	HTTPClient   *http.Client
+	BaseURL      string        // pages: /results, /watch, /playlist, /@handle
+	InnertubeURL string        // youtubei/v1 endpoints: search, player, next, browse
+	Headers      http.Header   // sent with every request, User-Agent included
+	Language     string        // hl, interface language of the responses
+	Region       string        // gl, content region
+	Timeout      time.Duration // limit of a single call, retries included; 0 waits for the context alone
+	Logger       *clog.Logger
+
+	// PlayerClient is the context video details are requested with, see InnertubeClients.
+	// The zero value means InnertubeWeb; search, next and browse always go out as WEB.
+	PlayerClient InnertubeClient
+	// Scrape skips youtubei/v1 for first pages and reads the HTML pages, which are otherwise
+	// only the fallback. Continuations always come from youtubei/v1.
+	Scrape bool
+
+	consented atomic.Bool // a consent page was seen, requests carry the consent cookies from then on
+	channels  sync.Map    // channel path → *models.ChannelInfo of the first pages served, for their continuations
+}
+
+// DefaultTimeout bounds every request of a client created by NewClient
+const DefaultTimeout = 30 * time.Second
+
+func NewClient() *Client {
+	headers := http.Header{}
+	headers.Set("User-Agent", utils.DefaultUserAgent)
+	headers.Set("Accept-Language", acceptLanguage("en", "US"))
+
+	return &Client{
+		HTTPClient:   httpx.NewClient(httpx.DefaultOptions),
+		BaseURL:      youtubeBase,
+		InnertubeURL: youtubeBase + "/youtubei/v1",
+		Headers:      headers,
+		Language:     "en",
+		Region:       "US",
+		Timeout:      DefaultTimeout,
+		Logger:       logger.Logger,
+	}
+}
+
+// SetLocale asks for responses in language (hl, e.g. "pt") with content for region (gl, e.g. "BR"),
+// in page queries, the innertube context and Accept-Language alike
+func (c *Client) SetLocale(language, region string) {
+	c.Language = language
+	c.Region = region
+	if c.Headers == nil {
+		c.Headers = http.Header{}
+	}
+	c.Headers.Set("Accept-Language", acceptLanguage(language, region))
+}
+
+// acceptLanguage builds "pt-BR,pt;q=0.9" out of "pt" and "BR"
+func acceptLanguage(language, region string) string {
+	base, _, hasRegion := strings.Cut(language, "-")
+	switch {
+	case hasRegion:
+		return language + "," + base + ";q=0.9"
+	case region != "":
+		return language + "-" + region + "," + language + ";q=0.9"
+	default:
+		return language
+	}
+}
+
+// fetch GETs a page relative to BaseURL, adding the locale to the query
+func (c *Client) fetch(ctx context.Context, path string) (string, error) {
+	u, err := url.Parse(c.resolve(path))
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	if c.Language != "" && q.Get("hl") == "" {
+		q.Set("hl", c.Language)
+	}
+	if c.Region != "" && q.Get("gl") == "" {
+		q.Set("gl", c.Region)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	body, err := c.do(req)
+	if err != nil || !isConsentPage(string(body)) || !c.consented.CompareAndSwap(false, true) {
+		return string(body), err
+	}
+
+	// EU sessions get the consent interstitial until the consent cookies are sent, once is enough
+	c.log().Info("[fetch] consent page, retrying with consent cookies", "path", u.Path)
+	req, err = http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	// the interstitial may have been cached under this URL
+	req.Header.Set("Cache-Control", "no-cache")
+
+	body, err = c.do(req)
+	return string(body), err
+}
+
+// do sends req with the client headers, cancelling it after Timeout
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	if c.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), c.Timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	for key, values := range c.Headers {
+		// headers of the request itself, like the User-Agent of an innertube client, win
+		if req.Header.Get(key) != "" {
+			continue
+		}
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if c.consented.Load() {
+		addConsentCookies(req, client.Jar)
+	}
+
+	body, err := utils.Do(client, req)
+	return body, classifyHTTPError(err)
+}
+
+// decode unmarshals data into one of the typed layouts. Fields whose type changed are skipped and
+// logged, so a layout change shows up in the debug log instead of failing the whole page.
+func (c *Client) decode(data []byte, v any) error {
+	err := json.Unmarshal(data, v)
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		c.log().Warn("[decode] unexpected field type", "field", typeErr.Field, "error", err)
+		return nil
+	}
+	if err != nil {
+		return layoutError(err)
+	}
+	return nil
+}
+
+// resolve maps paths and canonical youtube.com URLs onto BaseURL
+func (c *Client) resolve(ref string) string {
+	base := strings.TrimSuffix(c.BaseURL, "/")
+	if base == "" {
+		base = youtubeBase
+	}
+
+	if strings.HasPrefix(ref, "/") {
+		return base + ref
+	}
+	if rest, ok := strings.CutPrefix(ref, youtubeBase); ok {
+		return base + rest
+	}
+	return ref
+}
+
+func (c *Client) log() *clog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	if logger.Logger != nil {
+		return logger.Logger
+	}
+	return discardLogger
+}
+
+var discardLogger = clog.New(io.Discard)
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Drack112/go-youtube/internal/models"
+)
+
+// hangingClient returns a client whose server never answers until the request is abandoned
+func hangingClient(t *testing.T) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the server only notices a dropped connection once the body is read
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.HTTPClient = srv.Client()
+	c.BaseURL = srv.URL
+	c.InnertubeURL = srv.URL + "/youtubei/v1"
+	return c
+}
+
+func TestClientTimeout(t *testing.T) {
+	c := hangingClient(t)
+	c.Timeout = 50 * time.Millisecond
+
+	_, err := c.GetVideo(t.Context(), fixtureVideoID)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetVideo error = %v, want a deadline error", err)
+	}
+}
+
+func TestClientCancel(t *testing.T) {
+	c := hangingClient(t)
+	c.Timeout = 0
+
+	ctx, cancel := context.WithCancel(t.Context())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := c.SearchWithOptions(ctx, fixtureQuery, models.SearchOptions{}, "")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("SearchWithOptions error = %v, want context.Canceled", err)
+	}
+}
+
+func TestClientLocale(t *testing.T) {
+	var query url.Values
+	var acceptLang string
+	var payload struct {
+		Context struct {
+			Client struct {
+				HL string `json:"hl"`
+				GL string `json:"gl"`
+			} `json:"client"`
+		} `json:"context"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptLang = r.Header.Get("Accept-Language")
+		if r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&payload)
+			return
+		}
+		query = r.URL.Query()
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.HTTPClient = srv.Client()
+	c.BaseURL = srv.URL
+	c.InnertubeURL = srv.URL + "/youtubei/v1"
+	c.SetLocale("pt", "BR")
+
+	c.fetch(t.Context(), "/results?search_query=x")
+	if query.Get("hl") != "pt" || query.Get("gl") != "BR" {
+		t.Errorf("page query hl=%q gl=%q, want pt BR", query.Get("hl"), query.Get("gl"))
+	}
+	if acceptLang != "pt-BR,pt;q=0.9" {
+		t.Errorf("Accept-Language = %q", acceptLang)
+	}
+
+	c.postInnertube(t.Context(), InnertubeWeb, "search", map[string]any{})
+	if payload.Context.Client.HL != "pt" || payload.Context.Client.GL != "BR" {
+		t.Errorf("innertube context hl=%q gl=%q, want pt BR", payload.Context.Client.HL, payload.Context.Client.GL)
+	}
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// GetComments lists the top level comments of a video, most relevant first.
+// Pass the token of a previous response to fetch the next page.
+func (c *Client) GetComments(ctx context.Context, videoID string, continuationToken string) (*CommentsResponse, error) {
+	token := continuationToken
+	if token == "" {
+		c.log().Debug("[GetComments] looking up the comment section", "id", videoID)
+
+		next, err := c.watchNext(ctx, videoID)
+		if err != nil {
+			c.log().Error("[GetComments] next request failed", "error", err)
+			return nil, err
+		}
+		if token = commentSectionToken(next); token == "" {
+			return nil, ErrCommentsDisabled
+		}
+	}
+
+	body, err := c.postInnertube(ctx, InnertubeWeb, "next", map[string]any{"continuation": token})
+	if err != nil {
+		c.log().Error("[GetComments] request failed", "error", err)
+		return nil, err
+	}
+
+	var page commentsPage
+	if err := c.decode(body, &page); err != nil {
+		c.log().Error("[GetComments] failed to decode response", "error", err)
+		return nil, err
+	}
+
+	comments, continuation := parseComments(&page)
+	return &CommentsResponse{
+		Comments:          comments,
+		ContinuationToken: continuation,
+		HasMore:           continuation != "",
+	}, nil
+}
+
+// commentSectionToken finds the continuation that loads the comment section of a watch page
+func commentSectionToken(next *watchNext) string {
+	for _, item := range next.Contents.TwoColumnWatchNextResults.Results.Results.Contents {
+		section := item.ItemSectionRenderer
+		if section == nil || section.SectionIdentifier != "comment-item-section" {
+			continue
+		}
+		for _, entry := range section.Contents {
+			if entry.ContinuationItemRenderer != nil {
+				if token := entry.ContinuationItemRenderer.token(); token != "" {
+					return token
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// parseComments reads the comment threads of a next continuation. Current layouts only reference each
+// comment from its commentViewModel and ship the content as entity mutations in frameworkUpdates,
+// older ones embed a full commentRenderer.
+func parseComments(page *commentsPage) ([]models.Comment, string) {
+	entities := map[string]*commentEntityPayload{}
+	for _, m := range page.FrameworkUpdates.EntityBatchUpdate.Mutations {
+		if m.Payload.CommentEntityPayload != nil {
+			entities[m.EntityKey] = m.Payload.CommentEntityPayload
+		}
+	}
+
+	var comments []models.Comment
+	continuation := ""
+	for _, endpoint := range page.OnResponseReceivedEndpoints {
+		items := endpoint.ReloadContinuationItemsCommand.ContinuationItems
+		if items == nil {
+			items = endpoint.AppendContinuationItemsAction.ContinuationItems
+		}
+		for _, item := range items {
+			if thread := item.CommentThreadRenderer; thread != nil {
+				var comment *models.Comment
+				if vm := thread.CommentViewModel.CommentViewModel; vm != nil {
+					comment = parseCommentEntity(vm, entities[vm.CommentKey])
+				} else if r := thread.Comment.CommentRenderer; r != nil {
+					comment = parseCommentRenderer(r)
+				}
+				if comment != nil {
+					comments = append(comments, *comment)
+				}
+				continue
+			}
+
+			if item.ContinuationItemRenderer != nil {
+				if token := item.ContinuationItemRenderer.token(); token != "" {
+					continuation = token
+				}
+			}
+		}
+	}
+
+	return comments, continuation
+}
+
+func parseCommentEntity(vm *commentViewModel, payload *commentEntityPayload) *models.Comment {
+	if payload == nil || payload.Properties.CommentID == "" {
+		return nil
+	}
+
+	return &models.Comment{
+		ID:         payload.Properties.CommentID,
+		Author:     payload.Author.DisplayName,
+		AuthorID:   payload.Author.ChannelID,
+		Text:       payload.Properties.Content.Content,
+		Published:  payload.Properties.PublishedTime,
+		LikeCount:  utils.ParseCount(payload.Toolbar.LikeCountNotliked),
+		ReplyCount: int(utils.ParseCount(payload.Toolbar.ReplyCount)),
+		Pinned:     vm.PinnedText != "",
+		ByUploader: payload.Author.IsCreator,
+	}
+}
+
+func parseCommentRenderer(r *commentRenderer) *models.Comment {
+	if r.CommentID == "" {
+		return nil
+	}
+
+	return &models.Comment{
+		ID:         r.CommentID,
+		Author:     r.AuthorText.String(),
+		AuthorID:   r.AuthorEndpoint.BrowseEndpoint.BrowseID,
+		Text:       r.ContentText.String(),
+		Published:  r.PublishedTimeText.String(),
+		LikeCount:  utils.ParseCount(r.VoteCount.String()),
+		ReplyCount: int(r.ReplyCount),
+		Pinned:     r.PinnedCommentBadge != nil,
+		ByUploader: r.AuthorIsChannelOwner,
+	}
+}
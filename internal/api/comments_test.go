@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Drack112/go-youtube/internal/models"
+)
+
+const commentSectionNext = `{"contents":{"twoColumnWatchNextResults":{"results":{"results":{"contents":[
+	{"videoPrimaryInfoRenderer":{}},
+	{"itemSectionRenderer":{"sectionIdentifier":"comment-item-section","contents":[
+		{"continuationItemRenderer":{"continuationEndpoint":{"continuationCommand":{"token":"comments-token"}}}}
+	]}}
+]}}}}}`
+
+const commentEntities = `{
+	"onResponseReceivedEndpoints":[
+		{"reloadContinuationItemsCommand":{"slot":"RELOAD_CONTINUATION_SLOT_HEADER","continuationItems":[{"commentsHeaderRenderer":{}}]}},
+		{"reloadContinuationItemsCommand":{"continuationItems":[
+			{"commentThreadRenderer":{"commentViewModel":{"commentViewModel":{"commentKey":"key-1","pinnedText":"Pinned by @GoogleDevelopers"}}}},
+			{"commentThreadRenderer":{"comment":{"commentRenderer":{"commentId":"Ugy2","authorText":{"simpleText":"@rob"},
+				"authorEndpoint":{"browseEndpoint":{"browseId":"UCrob"}},"contentText":{"runs":[{"text":"Don't "},{"text":"communicate by sharing memory"}]},
+				"publishedTimeText":{"runs":[{"text":"3 years ago"}]},"voteCount":{"simpleText":"1.2K"},"replyCount":7,"authorIsChannelOwner":true}}}},
+			{"continuationItemRenderer":{"continuationEndpoint":{"continuationCommand":{"token":"page-2"}}}}
+		]}}
+	],
+	"frameworkUpdates":{"entityBatchUpdate":{"mutations":[
+		{"entityKey":"key-1","payload":{"commentEntityPayload":{
+			"properties":{"commentId":"Ugx1","content":{"content":"great talk"},"publishedTime":"2 years ago"},
+			"author":{"displayName":"@gopher","channelId":"UCgopher","isCreator":false},
+			"toolbar":{"likeCountNotliked":"31","replyCount":"4"}}}},
+		{"entityKey":"other","payload":{"engagementToolbarStateEntityPayload":{}}}
+	]}}
+}`
+
+func TestGetComments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			VideoID      string `json:"videoId"`
+			Continuation string `json:"continuation"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		switch {
+		case payload.VideoID == fixtureVideoID:
+			io.WriteString(w, commentSectionNext)
+		case payload.VideoID != "":
+			io.WriteString(w, `{"contents":{"twoColumnWatchNextResults":{"results":{"results":{"contents":[]}}}}}`)
+		case payload.Continuation == "comments-token":
+			io.WriteString(w, commentEntities)
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newRESTClient(srv)
+	c.InnertubeURL = srv.URL + "/youtubei/v1"
+
+	resp, err := c.GetComments(t.Context(), fixtureVideoID, "")
+	if err != nil {
+		t.Fatalf("GetComments: %v", err)
+	}
+	want := []models.Comment{
+		{ID: "Ugx1", Author: "@gopher", AuthorID: "UCgopher", Text: "great talk", Published: "2 years ago", LikeCount: 31, ReplyCount: 4, Pinned: true},
+		{ID: "Ugy2", Author: "@rob", AuthorID: "UCrob", Text: "Don't communicate by sharing memory", Published: "3 years ago", LikeCount: 1200, ReplyCount: 7, ByUploader: true},
+	}
+	if len(resp.Comments) != len(want) {
+		t.Fatalf("comments = %+v, want %+v", resp.Comments, want)
+	}
+	for i := range want {
+		if resp.Comments[i] != want[i] {
+			t.Errorf("comment %d = %+v, want %+v", i, resp.Comments[i], want[i])
+		}
+	}
+	if !resp.HasMore || resp.ContinuationToken != "page-2" {
+		t.Errorf("continuation = %q", resp.ContinuationToken)
+	}
+
+	if _, err := c.GetComments(t.Context(), fixtureLiveID, ""); !errors.Is(err, ErrCommentsDisabled) {
+		t.Errorf("want ErrCommentsDisabled for a watch page without a comment section, got %v", err)
+	}
+}
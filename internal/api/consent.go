@@ -0,0 +1,27 @@
+package api
+
+import "net/http"
+
+// consentCookies answer the consent interstitial. SOCS is what current frontends check,
+// CONSENT what older ones still look for.
+var consentCookies = []*http.Cookie{
+	{Name: "SOCS", Value: "CAI"},
+	{Name: "CONSENT", Value: "YES+"},
+}
+
+// addConsentCookies adds the consent cookies to req, leaving alone any the user imported into jar
+func addConsentCookies(req *http.Request, jar http.CookieJar) {
+	imported := map[string]bool{}
+	if jar != nil {
+		for _, cookie := range jar.Cookies(req.URL) {
+			imported[cookie.Name] = true
+		}
+	}
+
+	for _, cookie := range consentCookies {
+		if _, err := req.Cookie(cookie.Name); err == nil || imported[cookie.Name] {
+			continue
+		}
+		req.AddCookie(cookie)
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// consentServer plays an EU session: without the SOCS cookie every page redirects to the consent
+// interstitial, with it the saved search results are served. Only pages get the interstitial, so the
+// clients of these tests scrape.
+func consentServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	wall, err := os.ReadFile(filepath.Join(fixtureDir, "results_search_query_consent_wall.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := os.ReadFile(filepath.Join(fixtureDir, "results_search_query_golang_concurrency.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var walls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/m", func(w http.ResponseWriter, r *http.Request) {
+		walls.Add(1)
+		w.Write(wall)
+	})
+	mux.HandleFunc("/results", func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("SOCS"); err != nil || c.Value == "" {
+			http.Redirect(w, r, "/m?continue="+url.QueryEscape(r.URL.String()), http.StatusFound)
+			return
+		}
+		w.Write(results)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &walls
+}
+
+func TestConsentRedirectAccepted(t *testing.T) {
+	srv, walls := consentServer(t)
+
+	c := NewClient()
+	c.HTTPClient = srv.Client()
+	c.BaseURL = srv.URL
+	c.Scrape = true
+
+	for range 2 {
+		results, err := c.SearchVideos(t.Context(), fixtureQuery)
+		if err != nil {
+			t.Fatalf("SearchVideos: %v", err)
+		}
+		if len(results) == 0 {
+			t.Fatal("no results after accepting consent")
+		}
+	}
+
+	// the second search already carries the cookies
+	if walls.Load() != 1 {
+		t.Fatalf("consent page served %d times, want 1", walls.Load())
+	}
+}
+
+func TestConsentKeepsImportedCookies(t *testing.T) {
+	srv, walls := consentServer(t)
+
+	jar, _ := cookiejar.New(nil)
+	u, _ := url.Parse(srv.URL)
+	jar.SetCookies(u, []*http.Cookie{{Name: "SOCS", Value: "imported"}})
+
+	var seen []string
+	c := NewClient()
+	c.HTTPClient = srv.Client()
+	c.HTTPClient.Jar = jar
+	c.BaseURL = srv.URL
+	c.Scrape = true
+	c.HTTPClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		for _, cookie := range r.Cookies() {
+			if cookie.Name == "SOCS" {
+				seen = append(seen, cookie.Value)
+			}
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	c.consented.Store(true)
+
+	if _, err := c.SearchVideos(t.Context(), fixtureQuery); err != nil {
+		t.Fatalf("SearchVideos: %v", err)
+	}
+	if walls.Load() != 0 || len(seen) != 1 || seen[0] != "imported" {
+		t.Fatalf("sent SOCS %v with %d consent pages, want only the imported cookie", seen, walls.Load())
+	}
+}
+
+func TestConsentStillRequired(t *testing.T) {
+	wall, err := os.ReadFile(filepath.Join(fixtureDir, "results_search_query_consent_wall.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write(wall)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.HTTPClient = srv.Client()
+	c.BaseURL = srv.URL
+	c.Scrape = true
+
+	_, err = c.SearchVideos(t.Context(), fixtureQuery)
+	if !errors.Is(err, ErrConsentRequired) {
+		t.Fatalf("want ErrConsentRequired, got %v", err)
+	}
+	// one retry with the cookies, then give up
+	if calls.Load() != 2 {
+		t.Fatalf("%d requests, want 2", calls.Load())
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"errors"
+)
+
+// searchContinuation fetches the next page of a search using the token from the previous page
+func (c *Client) searchContinuation(ctx context.Context, token string) (*SearchResponse, error) {
+	c.log().Debug("[searchContinuation] fetching next page", "token", token)
+
+	body, err := c.postInnertube(ctx, InnertubeWeb, "search", map[string]any{
+		"continuation": token,
+	})
+	if err != nil {
+		c.log().Error("[searchContinuation] request failed", "error", err)
+		return nil, err
+	}
+
+	var resp continuationResponse
+	if err := c.decode(body, &resp); err != nil {
+		c.log().Error("[searchContinuation] failed to decode response", "error", err)
+		return nil, err
+	}
+
+	items, err := continuationItems(&resp)
+	if err != nil {
+		c.log().Error("[searchContinuation] failed to parse results", "error", err)
+		return nil, err
+	}
+
+	results := parseItems(items)
+	continuation := findContinuationToken(items)
+
+	return &SearchResponse{
+		Results:           results,
+		ContinuationToken: continuation,
+		HasMore:           continuation != "",
+	}, nil
+}
+
+// continuationItems returns the items a youtubei/v1 continuation appends.
+// Search answers with onResponseReceivedCommands, browse (playlists, channels) with onResponseReceivedActions.
+func continuationItems(resp *continuationResponse) ([]contentItem, error) {
+	actions := resp.OnResponseReceivedCommands
+	if actions == nil {
+		actions = resp.OnResponseReceivedActions
+	}
+	if actions == nil {
+		return nil, layoutError(errors.New("continuation commands missing"))
+	}
+
+	var items []contentItem
+	for i := range actions {
+		items = append(items, actions[i].AppendContinuationItemsAction.ContinuationItems...)
+		items = append(items, actions[i].ReloadContinuationItemsCommand.ContinuationItems...)
+	}
+	return items, nil
+}
+
+// findContinuationToken returns the token of the last continuationItemRenderer in items
+func findContinuationToken(items []contentItem) string {
+	continuation := ""
+	for i := range items {
+		renderer := items[i].ContinuationItemRenderer
+		if renderer == nil {
+			continue
+		}
+		if token := renderer.token(); token != "" {
+			continuation = token
+		}
+	}
+	return continuation
+}
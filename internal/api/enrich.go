@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+	"github.com/Drack112/go-youtube/pkg/workpool"
+)
+
+// EnrichOptions bounds an enrichment pass
+type EnrichOptions struct {
+	Workers int // videos fetched at once
+	PerHost int // of those, how many may go to the same host
+}
+
+// DefaultEnrichOptions leaves room for the page loads the user triggers meanwhile. A provider talks to one
+// host at a time, so PerHost is what normally applies; Workers caps the total while the jobs still
+// running on an instance that failed overlap with those sent to the next one.
+var DefaultEnrichOptions = EnrichOptions{Workers: 4, PerHost: 2}
+
+// Enrich fetches the video behind each video result not enriched yet, and hands every answer to found as
+// soon as it arrives. found is called from the workers, possibly at the same time. Enrich returns when
+// all results are done or ctx is cancelled.
+func Enrich(ctx context.Context, p Provider, results []models.SearchResult, opts EnrichOptions, found func(id string, video *models.Video, err error)) {
+	// asked per job, an instance failover moves the remaining jobs to the new host and its own limit
+	host := func() string { return providerHost(p) }
+
+	var jobs []workpool.Job
+	for _, r := range results {
+		if r.Kind != models.ResultVideo || r.ID == "" || r.Enriched {
+			continue
+		}
+		id := r.ID
+		jobs = append(jobs, workpool.Job{Host: host, Run: func(ctx context.Context) {
+			video, err := p.GetVideo(ctx, id)
+			if ctx.Err() == nil {
+				found(id, video, err)
+			}
+		}})
+	}
+
+	workpool.Run(ctx, workpool.Options{Workers: opts.Workers, PerHost: opts.PerHost}, jobs)
+}
+
+// EnrichResult copies what the video page knows and the result lacks: the description, keywords and
+// likes, and the duration search results leave out for shorts
+func EnrichResult(r *models.SearchResult, v *models.Video) {
+	r.Enriched = true
+	r.Description = v.Description
+	r.Keywords = v.Keywords
+	r.LikeCount = v.LikeCount
+
+	if r.Snippet == "" {
+		r.Snippet, _, _ = strings.Cut(strings.TrimSpace(v.Description), "\n")
+	}
+	if r.DurationSec == 0 && v.DurationSeconds > 0 && !v.IsLive {
+		r.DurationSec = v.DurationSeconds
+		r.Duration = utils.FormatDuration(v.DurationSeconds)
+	}
+	if r.ViewCount == 0 {
+		r.ViewCount = v.ViewCount
+	}
+	if r.ChannelID == "" {
+		r.ChannelID = v.Channel.ID
+		r.ChannelName = v.Channel.Name
+		r.ChannelURl = v.Channel.URL
+	}
+}
+
+// providerHost names the host the video requests of p go to, for the per-host limit
+func providerHost(p Provider) string {
+	switch p := p.(type) {
+	case *Client:
+		return hostOf(p.BaseURL)
+	case *InvidiousProvider:
+		return p.instances.host()
+	case *PipedProvider:
+		return p.instances.host()
+	}
+	return ""
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
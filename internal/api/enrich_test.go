@@ -0,0 +1,57 @@
+package api
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/Drack112/go-youtube/internal/models"
+)
+
+func TestEnrich(t *testing.T) {
+	c := newTestClient(t)
+
+	results := []models.SearchResult{
+		{ID: fixtureVideoID, Title: "Go Concurrency Patterns", IsShort: true},
+		{ID: fixturePlaylist, Kind: models.ResultPlaylist},
+		{ID: fixtureLiveID, Enriched: true},
+	}
+
+	var mu sync.Mutex
+	found := map[string]*models.Video{}
+	Enrich(t.Context(), c, results, DefaultEnrichOptions, func(id string, video *models.Video, err error) {
+		if err != nil {
+			t.Errorf("GetVideo(%s): %v", id, err)
+			return
+		}
+		mu.Lock()
+		found[id] = video
+		mu.Unlock()
+	})
+
+	if len(found) != 1 || found[fixtureVideoID] == nil {
+		t.Fatalf("enriched %v, want only the video not enriched yet", found)
+	}
+
+	r := results[0]
+	EnrichResult(&r, found[fixtureVideoID])
+	if !r.Enriched || r.LikeCount == 0 || len(r.Keywords) == 0 || r.Description == "" {
+		t.Errorf("details missing from %+v", r)
+	}
+	if r.DurationSec != found[fixtureVideoID].DurationSeconds || r.Duration == "" {
+		t.Errorf("duration = %q (%ds), want the one of the video page", r.Duration, r.DurationSec)
+	}
+	if r.Snippet != "Rob Pike" {
+		t.Errorf("snippet = %q, want the first line of the description", r.Snippet)
+	}
+}
+
+func TestProviderHost(t *testing.T) {
+	c := newTestClient(t)
+	if got, want := providerHost(c), hostOf(c.BaseURL); got != want || got == "" {
+		t.Errorf("client host = %q, want %q", got, want)
+	}
+	p := NewPipedProvider(c, []string{"https://pipedapi.example.org/", "https://other.example.net"})
+	if got := providerHost(p); got != "pipedapi.example.org" {
+		t.Errorf("piped host = %q", got)
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// Errors returned by the client. They arrive wrapped, match them with errors.Is.
+var (
+	ErrRateLimited      = errors.New("rate limited by YouTube")
+	ErrConsentRequired  = errors.New("YouTube requires cookie consent")
+	ErrVideoUnavailable = errors.New("video unavailable")
+	ErrAgeRestricted    = errors.New("video is age restricted")
+	ErrPrivate          = errors.New("video is private")
+	ErrRegionBlocked    = errors.New("video is blocked in this region")
+	ErrMembersOnly      = errors.New("video is for channel members only")
+	ErrLayoutChanged    = errors.New("unexpected page layout")
+	ErrCommentsDisabled = errors.New("comments are turned off for this video")
+)
+
+// PlayabilityError is the reason a watch page gives for not playing a video.
+// It unwraps to one of ErrVideoUnavailable, ErrAgeRestricted, ErrPrivate, ErrRegionBlocked,
+// ErrMembersOnly or ErrRateLimited.
+type PlayabilityError struct {
+	Status string // playabilityStatus.status, e.g. LOGIN_REQUIRED or UNPLAYABLE
+	Reason string // the message YouTube shows in the player
+	kind   error
+}
+
+func (e *PlayabilityError) Error() string {
+	if e.Reason == "" {
+		return e.kind.Error()
+	}
+	return e.kind.Error() + ": " + e.Reason
+}
+
+func (e *PlayabilityError) Unwrap() error {
+	return e.kind
+}
+
+// playabilityError reads the playabilityStatus of a player response; nil means the video can be played.
+// Upcoming streams report LIVE_STREAM_OFFLINE and are still worth showing.
+func playabilityError(ps *playabilityStatus) error {
+	status := ps.Status
+	if status == "" || status == "OK" || status == "LIVE_STREAM_OFFLINE" {
+		return nil
+	}
+
+	reason := ps.Reason
+	if reason == "" {
+		reason = ps.ErrorScreen.PlayerErrorMessageRenderer.Reason.String()
+	}
+	subreason := ps.ErrorScreen.PlayerErrorMessageRenderer.Subreason.String()
+	text := strings.ToLower(reason + " " + subreason)
+
+	kind := reasonKind(text)
+	switch {
+	case status == "AGE_CHECK_REQUIRED" || status == "AGE_VERIFICATION_REQUIRED" || ps.DesktopLegacyAgeGateReason != nil:
+		kind = ErrAgeRestricted
+	case kind == nil:
+		kind = ErrVideoUnavailable
+	}
+
+	return &PlayabilityError{Status: status, Reason: reason, kind: kind}
+}
+
+// reasonKind matches a lowercased error message against the phrases YouTube uses, nil when none matches
+func reasonKind(text string) error {
+	switch {
+	case containsAny(text, ageWords):
+		return ErrAgeRestricted
+	case containsAny(text, botWords):
+		return ErrRateLimited
+	case containsAny(text, privateWords):
+		return ErrPrivate
+	case containsAny(text, membersWords):
+		return ErrMembersOnly
+	case containsAny(text, regionWords):
+		return ErrRegionBlocked
+	case containsAny(text, unavailableWords):
+		return ErrVideoUnavailable
+	}
+	return nil
+}
+
+// Reason phrases in English, Portuguese, Spanish and German, lowercased
+var (
+	ageWords     = []string{"confirm your age", "age-restricted", "confirmar sua idade", "restrição de idade", "confirmar tu edad", "restricción de edad", "alter zu bestätigen", "altersbeschränkung"}
+	botWords     = []string{"not a bot", "não é um robô", "no eres un bot", "kein bot"}
+	privateWords = []string{"private", "privado", "privat"}
+	membersWords = []string{"members", "join this channel", "membros", "miembros", "mitglieder"}
+	regionWords  = []string{"country", "region", "location", "país", "região", "región", "deinem land", "ihrem land"}
+
+	unavailableWords = []string{"unavailable", "does not exist", "indisponível", "no está disponible", "nicht verfügbar"}
+)
+
+func containsAny(text string, words []string) bool {
+	for _, w := range words {
+		if strings.Contains(text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyHTTPError maps status codes YouTube uses for throttling onto ErrRateLimited
+func classifyHTTPError(err error) error {
+	var httpErr *utils.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %w", ErrRateLimited, err)
+	}
+	return err
+}
+
+// pageError explains why the expected data was missing from an HTML page
+func pageError(body string, err error) error {
+	if isConsentPage(body) {
+		return ErrConsentRequired
+	}
+	return layoutError(err)
+}
+
+func layoutError(err error) error {
+	return fmt.Errorf("%w: %v", ErrLayoutChanged, err)
+}
+
+// isConsentPage recognizes the "Before you continue" interstitial served to EU sessions, either in place of
+// the page or after a redirect to consent.youtube.com
+func isConsentPage(body string) bool {
+	return strings.Contains(body, `action="https://consent.youtube.com/`) || strings.Contains(body, `action="https://consent.google.com/`)
+}
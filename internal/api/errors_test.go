@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPlayabilityError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   error
+	}{
+		{"playable", `{"status":"OK"}`, nil},
+		{"upcoming stream", `{"status":"LIVE_STREAM_OFFLINE","reason":"Premieres in 2 hours"}`, nil},
+		{"removed", `{"status":"ERROR","reason":"This video has been removed by the uploader"}`, ErrVideoUnavailable},
+		{"private", `{"status":"LOGIN_REQUIRED","reason":"This video is private"}`, ErrPrivate},
+		{"age gate", `{"status":"LOGIN_REQUIRED","reason":"Sign in to confirm your age","desktopLegacyAgeGateReason":1}`, ErrAgeRestricted},
+		{"age check", `{"status":"AGE_CHECK_REQUIRED"}`, ErrAgeRestricted},
+		{"bot check", `{"status":"LOGIN_REQUIRED","reason":"Sign in to confirm you’re not a bot"}`, ErrRateLimited},
+		{"region", `{"status":"UNPLAYABLE","reason":"Video unavailable","errorScreen":{"playerErrorMessageRenderer":{"subreason":{"simpleText":"The uploader has not made this video available in your country"}}}}`, ErrRegionBlocked},
+		{"private pt", `{"status":"LOGIN_REQUIRED","reason":"Este vídeo é privado"}`, ErrPrivate},
+		{"age gate de", `{"status":"LOGIN_REQUIRED","reason":"Melde dich an, um dein Alter zu bestätigen"}`, ErrAgeRestricted},
+		{"region es", `{"status":"UNPLAYABLE","reason":"Video no disponible","errorScreen":{"playerErrorMessageRenderer":{"subreason":{"simpleText":"El propietario de este vídeo no lo ha puesto a disposición en tu país."}}}}`, ErrRegionBlocked},
+		{"members", `{"status":"UNPLAYABLE","reason":"Join this channel to get access to members-only content like this video, and other exclusive perks."}`, ErrMembersOnly},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var player playerResponse
+			if err := json.Unmarshal([]byte(`{"playabilityStatus":`+tt.status+`}`), &player); err != nil {
+				t.Fatal(err)
+			}
+
+			err := playabilityError(&player.PlayabilityStatus)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("want playable, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+
+			var pe *PlayabilityError
+			if !errors.As(err, &pe) || pe.Status == "" {
+				t.Fatalf("want a *PlayabilityError with its status, got %#v", err)
+			}
+		})
+	}
+}
+
+func TestRateLimitedStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.HTTPClient = srv.Client()
+	c.BaseURL = srv.URL
+
+	_, err := c.GetVideo(t.Context(), fixtureVideoID)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("want ErrRateLimited, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// extractJSONVar returns the JSON object assigned to name in an HTML page. It understands
+//
+//	var name = {...};
+//	window["name"] = {...};
+//	name = JSON.parse('...');
+//
+// and walks the object with string and escape awareness, so "};" inside strings does not end it.
+func extractJSONVar(html, name string) ([]byte, error) {
+	if data := extractJSONVars(html, name)[name]; data != nil {
+		return data, nil
+	}
+	return nil, fmt.Errorf("%s not found", name)
+}
+
+// extractJSONVars finds several assignments in one pass over html, jumping over every object it extracts.
+// Names that are never assigned an object are missing from the result.
+func extractJSONVars(html string, names ...string) map[string][]byte {
+	found := make(map[string][]byte, len(names))
+
+	for i := 0; i < len(html) && len(found) < len(names); i++ {
+		for _, name := range names {
+			if found[name] != nil || html[i] != name[0] || !strings.HasPrefix(html[i:], name) {
+				continue
+			}
+			if i > 0 && isIdentByte(html[i-1]) {
+				continue
+			}
+
+			pos, ok := skipAssignment(html, i+len(name))
+			if !ok {
+				continue
+			}
+
+			if html[pos] == '{' {
+				if end := objectEnd(html, pos); end > 0 {
+					found[name] = []byte(html[pos:end])
+					i = end - 1
+				}
+			} else if rest, ok := strings.CutPrefix(html[pos:], "JSON.parse("); ok {
+				if data, ok := decodeJSLiteral(strings.TrimLeft(rest, " \t\r\n")); ok && len(data) > 0 && data[0] == '{' {
+					found[name] = data
+				}
+			}
+			break
+		}
+	}
+
+	return found
+}
+
+// skipAssignment moves past `"]`, `']`, whitespace and the `=` that follow a variable name
+// and returns the position of the assigned value
+func skipAssignment(html string, pos int) (int, bool) {
+	if pos < len(html) && (html[pos] == '"' || html[pos] == '\'') {
+		pos++
+		if pos >= len(html) || html[pos] != ']' {
+			return 0, false
+		}
+		pos++
+	} else if pos < len(html) && isIdentByte(html[pos]) {
+		return 0, false
+	}
+
+	pos = skipSpace(html, pos)
+	if pos >= len(html) || html[pos] != '=' {
+		return 0, false
+	}
+	// "==" is a comparison, not an assignment
+	if pos+1 < len(html) && html[pos+1] == '=' {
+		return 0, false
+	}
+
+	pos = skipSpace(html, pos+1)
+	return pos, pos < len(html)
+}
+
+// objectEnd returns the index just past the brace that closes the object starting at start, or -1
+func objectEnd(s string, start int) int {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for i := start; i < len(s); i++ {
+		c := s[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return -1
+}
+
+// decodeJSLiteral decodes the quoted JavaScript string at the start of s
+func decodeJSLiteral(s string) ([]byte, bool) {
+	if s == "" || (s[0] != '\'' && s[0] != '"') {
+		return nil, false
+	}
+	quote := s[0]
+
+	out := make([]byte, 0, len(s))
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == quote {
+			return out, true
+		}
+		if c != '\\' {
+			out = append(out, c)
+			continue
+		}
+
+		i++
+		if i >= len(s) {
+			break
+		}
+
+		switch e := s[i]; e {
+		case 'n':
+			out = append(out, '\n')
+		case 't':
+			out = append(out, '\t')
+		case 'r':
+			out = append(out, '\r')
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case 'v':
+			out = append(out, '\v')
+		case '0':
+			out = append(out, 0)
+		case '\n':
+			// line continuation
+		case 'x':
+			if i+2 >= len(s) {
+				return nil, false
+			}
+			n, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return nil, false
+			}
+			out = utf8.AppendRune(out, rune(n))
+			i += 2
+		case 'u':
+			if i+4 >= len(s) {
+				return nil, false
+			}
+			n, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return nil, false
+			}
+			i += 4
+			r := rune(n)
+			// surrogate pairs arrive as two \u escapes
+			if utf16.IsSurrogate(r) && i+6 < len(s) && s[i+1] == '\\' && s[i+2] == 'u' {
+				if low, err := strconv.ParseUint(s[i+3:i+7], 16, 16); err == nil {
+					r = utf16.DecodeRune(r, rune(low))
+					i += 6
+				}
+			}
+			out = utf8.AppendRune(out, r)
+		default:
+			// \\, \', \", \/ and unknown escapes stand for the character itself
+			out = append(out, e)
+		}
+	}
+
+	return nil, false
+}
+
+func skipSpace(s string, pos int) int {
+	for pos < len(s) && (s[pos] == ' ' || s[pos] == '\t' || s[pos] == '\n' || s[pos] == '\r') {
+		pos++
+	}
+	return pos
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtractJSONVar(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "var assignment",
+			html: `<script>var ytInitialData = {"a":1};</script>`,
+			want: `{"a":1}`,
+		},
+		{
+			name: "closing sequence inside a string",
+			html: `<script>var ytInitialData = {"title":"if (x) {y};","n":[{"b":"}"}]};var other = {};</script>`,
+			want: `{"title":"if (x) {y};","n":[{"b":"}"}]}`,
+		},
+		{
+			name: "escaped quotes",
+			html: `<script>var ytInitialData = {"t":"say \"};\" \\","u":2};</script>`,
+			want: `{"t":"say \"};\" \\","u":2}`,
+		},
+		{
+			name: "window index",
+			html: `<script>window["ytInitialData"] = {"a":{"b":[1,2]}};</script>`,
+			want: `{"a":{"b":[1,2]}}`,
+		},
+		{
+			name: "json parse",
+			html: `<script>var ytInitialData = JSON.parse('{\x22a\x22:\x22it\x27s \u00e9\x22,\x22e\x22:\x22\ud83d\ude00\x22}');</script>`,
+			want: `{"a":"it's é","e":"😀"}`,
+		},
+		{
+			name: "skips reads and longer names",
+			html: `<script>if (window.ytInitialData == null) {} var ytInitialDataExtra = {"x":0}; ytInitialData={"a":true}</script>`,
+			want: `{"a":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractJSONVar(tt.html, "ytInitialData")
+			if err != nil {
+				t.Fatalf("extractJSONVar: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if !json.Valid(got) {
+				t.Errorf("extracted value is not valid JSON: %s", got)
+			}
+		})
+	}
+}
+
+func TestExtractJSONVarMissing(t *testing.T) {
+	for _, html := range []string{
+		`<html></html>`,
+		`<script>var ytInitialData = null;</script>`,
+		`<script>var ytInitialData = {"a":1</script>`,
+	} {
+		if _, err := extractJSONVar(html, "ytInitialData"); err == nil {
+			t.Errorf("expected an error for %q", html)
+		}
+	}
+}
+
+func TestExtractJSONVars(t *testing.T) {
+	html := `<script>var ytInitialPlayerResponse = {"a":"ytInitialData = {}"};</script>` +
+		`<script>var ytInitialData = {"b":2};</script>`
+
+	got := extractJSONVars(html, "ytInitialPlayerResponse", "ytInitialData", "ytMissing")
+	if s := string(got["ytInitialPlayerResponse"]); s != `{"a":"ytInitialData = {}"}` {
+		t.Errorf("ytInitialPlayerResponse = %q", s)
+	}
+	if s := string(got["ytInitialData"]); s != `{"b":2}` {
+		t.Errorf("ytInitialData = %q", s)
+	}
+	if _, ok := got["ytMissing"]; ok {
+		t.Error("ytMissing should not be in the result")
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// channelFeed is the Atom feed at /feeds/videos.xml. Elements are matched by local name, the yt: and
+// media: namespaces do not clash.
+type channelFeed struct {
+	Entries []struct {
+		VideoID string `xml:"videoId"`
+		Title   string `xml:"title"`
+		Link    struct {
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Author struct {
+			Name string `xml:"name"`
+			URI  string `xml:"uri"`
+		} `xml:"author"`
+		Published string `xml:"published"`
+		Group     struct {
+			Description string `xml:"description"`
+			Thumbnail   struct {
+				URL string `xml:"url,attr"`
+			} `xml:"thumbnail"`
+			Community struct {
+				Statistics struct {
+					Views int64 `xml:"views,attr"`
+				} `xml:"statistics"`
+			} `xml:"community"`
+		} `xml:"group"`
+	} `xml:"entry"`
+}
+
+// GetChannelFeed lists the latest uploads of a channel (15 at most) from its RSS feed. The feed is far
+// cheaper than the channel page and carries exact publish times, which makes it the one to poll.
+// Handles and custom URLs cost one resolve_url request first, UC ids none.
+func (c *Client) GetChannelFeed(ctx context.Context, ref string) ([]models.SearchResult, error) {
+	path := utils.ChannelPath(ref)
+	if path == "" {
+		return nil, fmt.Errorf("invalid channel reference: %s", ref)
+	}
+	id, err := c.channelBrowseID(ctx, path)
+	if err != nil {
+		c.log().Error("[GetChannelFeed] failed to resolve channel", "path", path, "error", err)
+		return nil, err
+	}
+
+	body, err := c.fetch(ctx, "/feeds/videos.xml?"+url.Values{"channel_id": {id}}.Encode())
+	if err != nil {
+		c.log().Error("[GetChannelFeed] request failed", "id", id, "error", err)
+		return nil, err
+	}
+
+	var feed channelFeed
+	if err := xml.Unmarshal([]byte(body), &feed); err != nil {
+		c.log().Error("[GetChannelFeed] failed to parse feed", "error", err)
+		return nil, layoutError(err)
+	}
+	return feedResults(feed, id, time.Now()), nil
+}
+
+func feedResults(feed channelFeed, channelID string, now time.Time) []models.SearchResult {
+	var results []models.SearchResult
+	for _, e := range feed.Entries {
+		if e.VideoID == "" {
+			continue
+		}
+
+		result := models.SearchResult{
+			ID:          e.VideoID,
+			Title:       e.Title,
+			URL:         youtubeWatchBase + e.VideoID,
+			Thumbnail:   e.Group.Thumbnail.URL,
+			ChannelName: e.Author.Name,
+			ChannelID:   channelID,
+			ChannelURl:  e.Author.URI,
+			IsShort:     strings.Contains(e.Link.Href, "/shorts/"),
+			ViewCount:   e.Group.Community.Statistics.Views,
+		}
+		if result.ChannelURl == "" {
+			result.ChannelURl = channelURL(channelID)
+		}
+		if result.Thumbnail == "" {
+			result.Thumbnail = "https://i.ytimg.com/vi/" + e.VideoID + "/hqdefault.jpg"
+		}
+		// the description is the full one, the first line makes the snippet
+		result.Snippet, _, _ = strings.Cut(strings.TrimSpace(e.Group.Description), "\n")
+
+		if published, err := time.Parse(time.RFC3339, e.Published); err == nil {
+			result.PublishedAt = published
+			result.PublishedAge = now.Sub(published)
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/base64"
+
+	"github.com/Drack112/go-youtube/internal/models"
+)
+
+// protobuf field numbers of the search "sp" parameter
+const (
+	spFieldSort    = 1
+	spFieldFilters = 2
+
+	filterFieldUpload          = 1
+	filterFieldType            = 2
+	filterFieldDuration        = 3
+	filterFieldHD              = 4
+	filterFieldSubtitles       = 5
+	filterFieldCreativeCommons = 6
+	filterFieldLive            = 8
+	filterFieldFourK           = 14
+	filterFieldHDR             = 25
+)
+
+// EncodeSearchFilter builds the base64 protobuf that youtube.com puts in the "sp" query parameter
+func EncodeSearchFilter(opts models.SearchOptions) string {
+	if opts.IsZero() {
+		return ""
+	}
+
+	var filters []byte
+	filters = appendVarintField(filters, filterFieldUpload, int(opts.Upload))
+	filters = appendVarintField(filters, filterFieldType, int(opts.Type))
+	filters = appendVarintField(filters, filterFieldDuration, int(opts.Duration))
+	filters = appendBoolField(filters, filterFieldHD, opts.Features.HD)
+	filters = appendBoolField(filters, filterFieldSubtitles, opts.Features.Subtitles)
+	filters = appendBoolField(filters, filterFieldCreativeCommons, opts.Features.CreativeCommons)
+	filters = appendBoolField(filters, filterFieldLive, opts.Features.Live)
+	filters = appendBoolField(filters, filterFieldFourK, opts.Features.FourK)
+	filters = appendBoolField(filters, filterFieldHDR, opts.Features.HDR)
+
+	var sp []byte
+	sp = appendVarintField(sp, spFieldSort, int(opts.Sort))
+	if len(filters) > 0 {
+		sp = appendVarint(sp, uint64(spFieldFilters<<3|2))
+		sp = appendVarint(sp, uint64(len(filters)))
+		sp = append(sp, filters...)
+	}
+
+	return base64.StdEncoding.EncodeToString(sp)
+}
+
+// appendVarintField writes a varint field, skipping zero values like proto3 does
+func appendVarintField(b []byte, field int, value int) []byte {
+	if value == 0 {
+		return b
+	}
+	b = appendVarint(b, uint64(field<<3))
+	return appendVarint(b, uint64(value))
+}
+
+func appendBoolField(b []byte, field int, value bool) []byte {
+	if !value {
+		return b
+	}
+	return appendVarintField(b, field, 1)
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// QualityOption is a selectable download/playback quality backed by formats the video really has
+type QualityOption struct {
+	Label   string // "1080p60 HDR", "audio", ...
+	Quality string // value understood by player.convertQualityToFormat: "1080p60", "2160p-hdr", "audio", ...
+	Height  int
+	Size    int64 // estimated bytes, 0 when unknown
+}
+
+// DefaultQualities is offered while the formats of a video are still unknown
+var DefaultQualities = []QualityOption{
+	{Label: "best", Quality: "best"},
+	{Label: "1080p", Quality: "1080p", Height: 1080},
+	{Label: "720p", Quality: "720p", Height: 720},
+	{Label: "480p", Quality: "480p", Height: 480},
+	{Label: "360p", Quality: "360p", Height: 360},
+	{Label: "audio", Quality: "audio"},
+}
+
+func parseStreamingData(video *models.Video, player *playerResponse) {
+	sd := player.StreamingData
+	if sd == nil {
+		return
+	}
+
+	durationMs := int64(video.DurationSeconds) * 1000
+	video.Formats = parseFormatList(sd.Formats, sd.ExpiresInSeconds, durationMs)
+	video.AdaptiveFormats = parseFormatList(sd.AdaptiveFormats, sd.ExpiresInSeconds, durationMs)
+}
+
+func parseFormatList(list []streamFormat, expires string, durationMs int64) []models.Format {
+	var formats []models.Format
+	for i := range list {
+		if format := parseFormat(&list[i], expires, durationMs); format.Itag != 0 {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+func parseFormat(f *streamFormat, expires string, durationMs int64) models.Format {
+	mimeType, codecs := splitMimeType(f.MimeType)
+
+	format := models.Format{
+		Itag:          int(f.Itag),
+		QualityLabel:  f.QualityLabel,
+		MimeType:      mimeType,
+		Codecs:        codecs,
+		Bitrate:       int(f.Bitrate),
+		Width:         int(f.Width),
+		Height:        int(f.Height),
+		FPS:           int(f.FPS),
+		AudioQuality:  f.AudioQuality,
+		AudioChannels: int(f.AudioChannels),
+		URL:           f.URL,
+		Expires:       expires,
+		ContentLength: int64(f.ContentLength),
+	}
+
+	if format.ContentLength == 0 && format.Bitrate > 0 {
+		// estimate from the average bitrate when YouTube omits the length
+		ms := durationMs
+		if f.ApproxDurationMs > 0 {
+			ms = int64(f.ApproxDurationMs)
+		}
+		bitrate := int64(f.AverageBitrate)
+		if bitrate == 0 {
+			bitrate = int64(format.Bitrate)
+		}
+		format.ContentLength = bitrate * ms / 8000
+	}
+
+	format.IsAudioOnly = strings.HasPrefix(mimeType, "audio/")
+	format.IsVideoOnly = strings.HasPrefix(mimeType, "video/") && !strings.Contains(codecs, ",") && format.AudioQuality == ""
+
+	transfer := f.ColorInfo.TransferCharacteristics
+	format.IsHDR = strings.Contains(format.QualityLabel, "HDR") ||
+		transfer == "COLOR_TRANSFER_CHARACTERISTICS_SMPTEST2084" ||
+		transfer == "COLOR_TRANSFER_CHARACTERISTICS_ARIB_STD_B67"
+
+	return format
+}
+
+// splitMimeType turns `video/mp4; codecs="avc1.4d401e, mp4a.40.2"` into its type and codec list
+func splitMimeType(raw string) (string, string) {
+	mimeType, params, _ := strings.Cut(raw, ";")
+	codecs := ""
+	if _, after, ok := strings.Cut(params, "codecs="); ok {
+		codecs = strings.Trim(strings.TrimSpace(after), `"`)
+	}
+	return strings.TrimSpace(mimeType), codecs
+}
+
+// AvailableQualities lists the qualities that exist for video, best first, with estimated sizes
+func AvailableQualities(video *models.Video) []QualityOption {
+	if video == nil || len(video.Formats)+len(video.AdaptiveFormats) == 0 {
+		return DefaultQualities
+	}
+
+	all := append(append([]models.Format{}, video.Formats...), video.AdaptiveFormats...)
+
+	var bestAudio int64
+	for _, f := range all {
+		if f.IsAudioOnly && f.ContentLength > bestAudio {
+			bestAudio = f.ContentLength
+		}
+	}
+
+	// keep the largest stream for every height/fps/HDR combination
+	type variant struct {
+		height int
+		fps    int
+		hdr    bool
+	}
+	sizes := map[variant]int64{}
+	for _, f := range all {
+		if f.IsAudioOnly || f.Height == 0 {
+			continue
+		}
+		size := f.ContentLength
+		if f.IsVideoOnly {
+			size += bestAudio
+		}
+		v := variant{height: f.Height, fps: f.FPS, hdr: f.IsHDR}
+		if cur, ok := sizes[v]; !ok || size > cur {
+			sizes[v] = size
+		}
+	}
+
+	variants := make([]variant, 0, len(sizes))
+	for v := range sizes {
+		variants = append(variants, v)
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		a, b := variants[i], variants[j]
+		if a.height != b.height {
+			return a.height > b.height
+		}
+		if a.fps != b.fps {
+			return a.fps > b.fps
+		}
+		return a.hdr && !b.hdr
+	})
+
+	var options []QualityOption
+	if len(variants) > 0 {
+		options = append(options, QualityOption{Label: "best", Quality: "best", Height: variants[0].height, Size: sizes[variants[0]]})
+	}
+
+	for _, v := range variants {
+		label := fmt.Sprintf("%dp", v.height)
+		quality := label
+		if v.fps > 30 {
+			label += strconv.Itoa(v.fps)
+			quality = label
+		}
+		if v.hdr {
+			label += " HDR"
+			quality += "-hdr"
+		}
+		options = append(options, QualityOption{Label: label, Quality: quality, Height: v.height, Size: sizes[v]})
+	}
+
+	if bestAudio > 0 {
+		options = append(options, QualityOption{Label: "audio", Quality: "audio", Size: bestAudio})
+	}
+
+	return options
+}
+
+// ResolveQuality maps a requested quality ("720p", "best", "audio") onto the closest available option.
+// The boolean is false when the exact quality does not exist for the video.
+func ResolveQuality(options []QualityOption, requested string) (QualityOption, bool) {
+	if len(options) == 0 {
+		return QualityOption{Label: requested, Quality: requested}, false
+	}
+
+	requested = strings.ToLower(strings.TrimSpace(requested))
+	for _, o := range options {
+		if strings.ToLower(o.Quality) == requested || strings.ToLower(o.Label) == requested {
+			return o, true
+		}
+	}
+
+	height := utils.ParseQualityHeight(requested)
+	if height == 0 {
+		return options[0], false
+	}
+
+	// closest quality that does not exceed the requested resolution
+	for _, o := range options {
+		if o.Height > 0 && o.Height <= height && o.Quality != "best" {
+			return o, o.Height == height
+		}
+	}
+
+	// nothing small enough, fall back to the lowest video quality
+	for i := len(options) - 1; i >= 0; i-- {
+		if options[i].Height > 0 {
+			return options[i], false
+		}
+	}
+	return options[0], false
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Drack112/go-youtube/internal/models"
+)
+
+func TestAvailableQualitiesHDR(t *testing.T) {
+	video := &models.Video{AdaptiveFormats: []models.Format{
+		{Height: 1080, FPS: 60, IsVideoOnly: true, IsHDR: true, ContentLength: 300},
+		{Height: 1080, FPS: 60, IsVideoOnly: true, ContentLength: 200},
+		{IsAudioOnly: true, ContentLength: 10},
+	}}
+
+	var got []string
+	for _, o := range AvailableQualities(video) {
+		got = append(got, o.Label+"="+o.Quality)
+	}
+	want := []string{"best=best", "1080p60 HDR=1080p60-hdr", "1080p60=1080p60", "audio=audio"}
+	if len(got) != len(want) {
+		t.Fatalf("qualities = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("quality %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	if o, exact := ResolveQuality(AvailableQualities(video), "1080p60"); !exact || o.Quality != "1080p60" {
+		t.Errorf("1080p60 resolved to %+v, the SDR stream was asked for", o)
+	}
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// The suite runs every api call against pages saved in testdata/fixtures, served by a local httptest server.
+// The pages in the tree are hand-written in youtube.com's shape, not captures; testdata/fixtures/README.md
+// lists them until -record replaces them.
+//
+//	go test ./internal/api                  offline, compares against testdata/golden
+//	go test ./internal/api -update          rewrites the golden files from the current parser output
+//	go test ./internal/api -record -update  refreshes the fixtures from youtube.com first
+var (
+	record = flag.Bool("record", false, "fetch fixtures from youtube.com and save them to testdata/fixtures")
+	update = flag.Bool("update", false, "rewrite testdata/golden with the current output")
+)
+
+// Inputs the fixtures were saved for. Recording fetches these, so they must point at real content.
+const (
+	fixtureQuery    = "golang concurrency"
+	fixtureVideoID  = "f6kdp27TYZs"
+	fixtureLiveID   = "jfKfPfyJRdk"
+	fixturePlaylist = "PLtLJO5JKE5YDKG4WcaNts3IVZqhDmmuBH"
+	fixtureChannel  = "@GoogleDevelopers"
+	consentQuery    = "consent wall"
+)
+
+const (
+	fixtureDir = "testdata/fixtures"
+	goldenDir  = "testdata/golden"
+)
+
+// newTestClient returns a client whose every request is answered from testdata/fixtures
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(fixtureHandler(t))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.HTTPClient = srv.Client()
+	c.BaseURL = srv.URL
+	c.InnertubeURL = srv.URL + "/youtubei/v1"
+	return c
+}
+
+func fixtureHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		name := fixtureName(r, body)
+		path := filepath.Join(fixtureDir, name)
+
+		if *record {
+			data, err := recordFixture(r, body, path)
+			if err != nil {
+				t.Errorf("recording %s: %v", name, err)
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			w.Write(data)
+			return
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("missing fixture %s for %s %s (run with -record to fetch it)", name, r.Method, r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}
+}
+
+// recordFixture replays the request against youtube.com and saves the answer
+func recordFixture(r *http.Request, body []byte, path string) ([]byte, error) {
+	req, err := http.NewRequest(r.Method, youtubeBase+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range []string{"User-Agent", "Accept-Language", "Content-Type", "X-YouTube-Client-Name", "X-YouTube-Client-Version"} {
+		if v := r.Header.Get(h); v != "" {
+			req.Header.Set(h, v)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return data, os.WriteFile(path, data, 0o644)
+}
+
+var unsafeFixtureChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// fixtureName maps a request onto a file name: the path and query for pages, the payload fields that pick
+// the content (continuation, videoId, query, browseId, params, url) for youtubei calls. Locale parameters are ignored so fixtures do not depend on the client configuration.
+func fixtureName(r *http.Request, body []byte) string {
+	key := r.URL.Path
+	ext := ".html"
+
+	if strings.HasPrefix(r.URL.Path, "/youtubei/") {
+		var payload struct {
+			Continuation string `json:"continuation"`
+			VideoID      string `json:"videoId"`
+			Query        string `json:"query"`
+			BrowseID     string `json:"browseId"`
+			Params       string `json:"params"`
+			URL          string `json:"url"`
+		}
+		json.Unmarshal(body, &payload)
+		for _, field := range []string{payload.Continuation, payload.VideoID, payload.Query, payload.BrowseID, payload.Params, payload.URL} {
+			if field != "" {
+				key += "_" + field
+			}
+		}
+		ext = ".json"
+	} else {
+		q := r.URL.Query()
+		q.Del("hl")
+		q.Del("gl")
+		if enc := q.Encode(); enc != "" {
+			key += "_" + enc
+		}
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/api/"):
+			ext = ".json"
+		case strings.HasPrefix(r.URL.Path, "/feeds/"):
+			ext = ".xml"
+		}
+	}
+
+	name := strings.Trim(unsafeFixtureChars.ReplaceAllString(key, "_"), "_")
+	if len(name) > 180 {
+		// real continuation tokens easily exceed file name limits
+		sum := sha1.Sum([]byte(name))
+		name = name[:160] + "_" + hex.EncodeToString(sum[:6])
+	}
+	return name + ext
+}
+
+// assertGolden compares got, encoded as indented JSON, with testdata/golden/<name>.json
+func assertGolden(t *testing.T, name string, got any) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(got); err != nil {
+		t.Fatalf("encoding %s: %v", name, err)
+	}
+	data := buf.Bytes()
+
+	path := filepath.Join(goldenDir, name+".json")
+	if *update {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("writing golden %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden file %s (run with -update to create it)", path)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("%s does not match %s, run with -update and review the diff\n\ngot:\n%s", name, path, data)
+	}
+}
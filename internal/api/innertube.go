@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Drack112/go-youtube/pkg/httpx"
+)
+
+// InnertubeClient is a client context youtubei/v1 requests identify themselves with. Player answers
+// differ per client: the Android and embedded TV players skip checks of the web player and list other
+// formats, so switching clients can get a video past an error the watch page shows.
+type InnertubeClient struct {
+	Name      string         // context.client.clientName
+	ID        int            // X-YouTube-Client-Name, the numeric form of Name
+	Version   string         // context.client.clientVersion
+	UserAgent string         // replaces the User-Agent of the Client when set
+	Extra     map[string]any // further context.client fields, e.g. the Android SDK version
+	Embedded  bool           // embedded players also want the page they are embedded in
+}
+
+var (
+	InnertubeWeb = InnertubeClient{Name: "WEB", ID: 1, Version: "2.20251014.01.00"}
+
+	InnertubeMWeb = InnertubeClient{
+		Name:      "MWEB",
+		ID:        2,
+		Version:   "2.20251014.01.00",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 18_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.6 Mobile/15E148 Safari/604.1",
+	}
+
+	InnertubeAndroid = InnertubeClient{
+		Name:      "ANDROID",
+		ID:        3,
+		Version:   "20.10.38",
+		UserAgent: "com.google.android.youtube/20.10.38 (Linux; U; Android 11) gzip",
+		Extra:     map[string]any{"androidSdkVersion": 30, "osName": "Android", "osVersion": "11"},
+	}
+
+	InnertubeTVEmbedded = InnertubeClient{Name: "TVHTML5_SIMPLY_EMBEDDED_PLAYER", ID: 85, Version: "2.0", Embedded: true}
+)
+
+// InnertubeClients maps the short names users pick clients by onto their contexts
+var InnertubeClients = map[string]InnertubeClient{
+	"web":     InnertubeWeb,
+	"mweb":    InnertubeMWeb,
+	"android": InnertubeAndroid,
+	"tv":      InnertubeTVEmbedded,
+}
+
+// channelTabParams select a tab in a browse request, as the tab links of a channel page carry them
+var channelTabParams = map[ChannelTab]string{
+	ChannelTabVideos:    "EgZ2aWRlb3PyBgQKAjoA",
+	ChannelTabShorts:    "EgZzaG9ydHPyBgUKA5oBAA==",
+	ChannelTabLive:      "EgdzdHJlYW1z8gYECgJ6AA==",
+	ChannelTabPlaylists: "EglwbGF5bGlzdHPyBgQKAkIA",
+}
+
+// innertubeContext builds the "context" object expected by youtubei/v1 endpoints
+func (c *Client) innertubeContext(client InnertubeClient) map[string]any {
+	fields := map[string]any{
+		"clientName":    client.Name,
+		"clientVersion": client.Version,
+		"hl":            c.Language,
+		"gl":            c.Region,
+	}
+	for k, v := range client.Extra {
+		fields[k] = v
+	}
+
+	ctx := map[string]any{"client": fields}
+	if client.Embedded {
+		ctx["thirdParty"] = map[string]any{"embedUrl": youtubeBase + "/"}
+	}
+	return ctx
+}
+
+// postInnertube sends payload to a youtubei/v1 endpoint such as "search" or "browse", as client
+func (c *Client) postInnertube(ctx context.Context, client InnertubeClient, endpoint string, payload map[string]any) ([]byte, error) {
+	payload["context"] = c.innertubeContext(client)
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(c.InnertubeURL, "/")+"/"+endpoint+"?prettyPrint=false", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-YouTube-Client-Name", strconv.Itoa(client.ID))
+	req.Header.Set("X-YouTube-Client-Version", client.Version)
+	if client.UserAgent != "" {
+		req.Header.Set("User-Agent", client.UserAgent)
+	}
+	// youtubei calls only read, so they can be retried like a GET
+	httpx.MarkIdempotent(req)
+
+	return c.do(req)
+}
+
+// playerClient is the context of player requests, WEB unless PlayerClient picks another
+func (c *Client) playerClient() InnertubeClient {
+	if c.PlayerClient.Name == "" {
+		return InnertubeWeb
+	}
+	return c.PlayerClient
+}
+
+// scrapeAfter reports whether a failed youtubei/v1 call is worth repeating on the HTML page. The endpoint
+// may reject the client context or move to a layout the parser does not know before the pages do, while
+// cancellations, rate limits and unplayable videos fail the same way on both.
+func scrapeAfter(ctx context.Context, err error) bool {
+	var playability *PlayabilityError
+	return ctx.Err() == nil && !errors.Is(err, ErrRateLimited) && !errors.As(err, &playability)
+}
+
+// channelBrowseID resolves a channel path from utils.ChannelPath to the UC id browse requests take
+func (c *Client) channelBrowseID(ctx context.Context, path string) (string, error) {
+	if id, ok := strings.CutPrefix(path, "/channel/"); ok {
+		return id, nil
+	}
+
+	body, err := c.postInnertube(ctx, InnertubeWeb, "navigation/resolve_url", map[string]any{
+		"url": youtubeBase + path,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Endpoint struct {
+			BrowseEndpoint struct {
+				BrowseID string `json:"browseId"`
+			} `json:"browseEndpoint"`
+		} `json:"endpoint"`
+	}
+	if err := c.decode(body, &resp); err != nil {
+		return "", err
+	}
+	if id := resp.Endpoint.BrowseEndpoint.BrowseID; id != "" {
+		return id, nil
+	}
+	return "", layoutError(errors.New("resolve_url returned no browse endpoint"))
+}
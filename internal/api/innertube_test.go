@@ -0,0 +1,170 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestScrapedPages reads the same content out of the HTML pages, which must match what youtubei/v1 gave
+func TestScrapedPages(t *testing.T) {
+	c := newTestClient(t)
+	c.Scrape = true
+
+	search, err := c.SearchVideosWithPagination(t.Context(), fixtureQuery, "")
+	if err != nil {
+		t.Fatalf("SearchVideosWithPagination: %v", err)
+	}
+	assertGolden(t, "search", search)
+
+	video, err := c.GetVideo(t.Context(), fixtureVideoID)
+	if err != nil {
+		t.Fatalf("GetVideo: %v", err)
+	}
+	video.PlayerResponse = nil
+	assertGolden(t, "video", video)
+
+	playlist, err := c.GetPlaylist(t.Context(), fixturePlaylist, "")
+	if err != nil {
+		t.Fatalf("GetPlaylist: %v", err)
+	}
+	assertGolden(t, "playlist", playlist)
+
+	for _, tab := range []ChannelTab{ChannelTabVideos, ChannelTabPlaylists} {
+		channel, err := c.GetChannel(t.Context(), fixtureChannel, tab, "")
+		if err != nil {
+			t.Fatalf("GetChannel(%s): %v", tab, err)
+		}
+		assertGolden(t, "channel_"+string(tab), channel)
+	}
+}
+
+// failingInnertube answers youtubei/v1 calls with status, and everything else from the fixtures
+func failingInnertube(t *testing.T, status int) (*Client, *atomic.Int32) {
+	t.Helper()
+
+	var pages atomic.Int32
+	fixtures := fixtureHandler(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/youtubei/") {
+			http.Error(w, "bad client", status)
+			return
+		}
+		pages.Add(1)
+		fixtures(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.HTTPClient = srv.Client()
+	c.BaseURL = srv.URL
+	c.InnertubeURL = srv.URL + "/youtubei/v1"
+	return c, &pages
+}
+
+func TestInnertubeFallsBackToPages(t *testing.T) {
+	c, pages := failingInnertube(t, http.StatusBadRequest)
+
+	video, err := c.GetVideo(t.Context(), fixtureVideoID)
+	if err != nil {
+		t.Fatalf("GetVideo: %v", err)
+	}
+	if video.ID != fixtureVideoID || pages.Load() != 1 {
+		t.Fatalf("got video %q after %d page requests, want the watch page", video.ID, pages.Load())
+	}
+
+	if _, err := c.GetChannel(t.Context(), fixtureChannel, ChannelTabVideos, ""); err != nil {
+		t.Fatalf("GetChannel: %v", err)
+	}
+}
+
+func TestInnertubeRateLimitNoFallback(t *testing.T) {
+	c, pages := failingInnertube(t, http.StatusTooManyRequests)
+
+	_, err := c.SearchVideos(t.Context(), fixtureQuery)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("want ErrRateLimited, got %v", err)
+	}
+	if pages.Load() != 0 {
+		t.Fatal("rate limited search was repeated on the results page")
+	}
+}
+
+func TestInnertubeUnplayableNoFallback(t *testing.T) {
+	var pages atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/player") {
+			io.WriteString(w, `{"playabilityStatus":{"status":"ERROR","reason":"This video is private"}}`)
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, "/youtubei/") {
+			pages.Add(1)
+		}
+		io.WriteString(w, `{}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.HTTPClient = srv.Client()
+	c.BaseURL = srv.URL
+	c.InnertubeURL = srv.URL + "/youtubei/v1"
+
+	_, err := c.GetVideo(t.Context(), fixtureVideoID)
+	if !errors.Is(err, ErrPrivate) {
+		t.Fatalf("want ErrPrivate, got %v", err)
+	}
+	if pages.Load() != 0 {
+		t.Fatal("unplayable video was looked up on the watch page")
+	}
+}
+
+func TestInnertubePlayerClient(t *testing.T) {
+	type call struct {
+		clientName, userAgent string
+		context               map[string]any
+	}
+	var mu sync.Mutex
+	calls := map[string]call{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Context map[string]any `json:"context"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		mu.Lock()
+		calls[r.URL.Path] = call{r.Header.Get("X-YouTube-Client-Name"), r.Header.Get("User-Agent"), payload.Context}
+		mu.Unlock()
+		io.WriteString(w, `{"videoDetails":{"videoId":"`+fixtureVideoID+`","title":"t"}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.HTTPClient = srv.Client()
+	c.InnertubeURL = srv.URL + "/youtubei/v1"
+	c.PlayerClient = InnertubeAndroid
+
+	if _, err := c.GetVideo(t.Context(), fixtureVideoID); err != nil {
+		t.Fatalf("GetVideo: %v", err)
+	}
+
+	player := calls["/youtubei/v1/player"]
+	client, _ := player.context["client"].(map[string]any)
+	if player.clientName != "3" || player.userAgent != InnertubeAndroid.UserAgent || client["clientName"] != "ANDROID" || client["androidSdkVersion"] != 30.0 {
+		t.Errorf("player request went out as %+v", player)
+	}
+	// next keeps the web layout the parser reads
+	if next := calls["/youtubei/v1/next"]; next.clientName != "1" || next.userAgent == InnertubeAndroid.UserAgent {
+		t.Errorf("next request went out as %+v", next)
+	}
+
+	if ctx := c.innertubeContext(InnertubeTVEmbedded); ctx["thirdParty"] == nil {
+		t.Error("embedded player context without thirdParty.embedUrl")
+	}
+}
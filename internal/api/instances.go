@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Drack112/go-youtube/pkg/httpx"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// instancePool is the list of instances of a REST backend. Requests start at the instance that answered
+// last and move down the list while instances are down, blocked by YouTube or answer with garbage.
+type instancePool struct {
+	backend string
+	urls    []string
+	current atomic.Int32
+}
+
+func newInstancePool(backend string, urls []string) *instancePool {
+	pool := &instancePool{backend: backend}
+	for _, u := range urls {
+		if u = strings.TrimSuffix(strings.TrimSpace(u), "/"); u != "" {
+			pool.urls = append(pool.urls, u)
+		}
+	}
+	return pool
+}
+
+// host is the host of the instance requests go to first
+func (p *instancePool) host() string {
+	if len(p.urls) == 0 {
+		return ""
+	}
+	return hostOf(p.urls[int(p.current.Load())%len(p.urls)])
+}
+
+// get fetches ref, a path with its query, and decodes the JSON answer into v
+func (p *instancePool) get(ctx context.Context, c *Client, ref string, v any) error {
+	return p.try(ctx, c, ref, func(body []byte) error {
+		return json.Unmarshal(body, v)
+	})
+}
+
+// getRaw fetches ref and returns the body as is; absolute URLs are tried once, on no instance in particular
+func (p *instancePool) getRaw(ctx context.Context, c *Client, ref string) ([]byte, error) {
+	if !strings.HasPrefix(ref, "/") {
+		req, err := http.NewRequestWithContext(ctx, "GET", ref, nil)
+		if err != nil {
+			return nil, err
+		}
+		return c.do(req)
+	}
+
+	var data []byte
+	err := p.try(ctx, c, ref, func(body []byte) error {
+		data = body
+		return nil
+	})
+	return data, err
+}
+
+// try requests ref from one instance after the other until accept takes an answer or an error shows that
+// every instance would fail the same way
+func (p *instancePool) try(ctx context.Context, c *Client, ref string, accept func([]byte) error) error {
+	if len(p.urls) == 0 {
+		return fmt.Errorf("no %s instances configured", p.backend)
+	}
+
+	var errs []error
+	start := int(p.current.Load())
+	for i := range p.urls {
+		n := (start + i) % len(p.urls)
+		base := p.urls[n]
+
+		err := p.request(ctx, c, base+ref, accept)
+		if err == nil {
+			p.current.Store(int32(n))
+			return nil
+		}
+		if ctx.Err() != nil || !failOver(err) {
+			return err
+		}
+
+		c.log().Warn("[instancePool] instance failed, trying the next one", "backend", p.backend, "instance", base, "error", err)
+		errs = append(errs, fmt.Errorf("%s: %w", base, err))
+	}
+	return fmt.Errorf("every %s instance failed: %w", p.backend, errors.Join(errs...))
+}
+
+func (p *instancePool) request(ctx context.Context, c *Client, url string, accept func([]byte) error) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	body, err := c.do(req)
+	if err != nil {
+		return instanceError(body, err)
+	}
+	// a page that does not decode is usually the error page of a proxy in front of the instance
+	if err := accept(body); err != nil {
+		return layoutError(err)
+	}
+	return nil
+}
+
+// instanceError adds the message of a JSON error body to err. Both Invidious and Piped answer
+// {"error": "..."}, Piped also sends a readable "message". A 404 without such a body comes from a proxy or
+// an instance that does not serve the API, not from the API saying the content is missing.
+func instanceError(body []byte, err error) error {
+	var answer struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	msg := ""
+	if json.Unmarshal(body, &answer) == nil {
+		msg = answer.Message
+		if msg == "" {
+			msg = answer.Error
+		}
+	}
+
+	var httpErr *utils.HTTPError
+	if msg == "" {
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%w: %w", ErrLayoutChanged, err)
+		}
+		return err
+	}
+	if kind := reasonKind(strings.ToLower(msg)); kind != nil {
+		// both stay visible: failOver looks at the status first, the caller at the reason
+		return fmt.Errorf("%w: %s: %w", kind, msg, err)
+	}
+	return fmt.Errorf("%w: %s", err, msg)
+}
+
+// failOver reports whether another instance may answer where this one failed. Network errors, rate limits,
+// server errors, broken answers and region blocks are local to an instance; missing content, private
+// videos and bad requests are not.
+func failOver(err error) bool {
+	if errors.Is(err, httpx.ErrOffline) {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrLayoutChanged) || errors.Is(err, ErrRegionBlocked) {
+		return true
+	}
+	// an overloaded instance words its error page like missing content
+	var httpErr *utils.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode >= 500 {
+		return true
+	}
+	for _, kind := range []error{ErrVideoUnavailable, ErrPrivate, ErrAgeRestricted, ErrMembersOnly} {
+		if errors.Is(err, kind) {
+			return false
+		}
+	}
+
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusForbidden
+	}
+	return true
+}
@@ -0,0 +1,569 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// InvidiousProvider reads content from the /api/v1 REST API of Invidious instances.
+// Its continuation tokens are the path of the next request, valid on any instance.
+type InvidiousProvider struct {
+	client    *Client
+	instances *instancePool
+}
+
+// NewInvidiousProvider sends requests through c to the first of instances that answers
+func NewInvidiousProvider(c *Client, instances []string) *InvidiousProvider {
+	return &InvidiousProvider{client: c, instances: newInstancePool("invidious", instances)}
+}
+
+func (p *InvidiousProvider) Name() string {
+	return "invidious"
+}
+
+type invidiousThumbnail struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type invidiousItem struct {
+	Type              string               `json:"type"`
+	Title             string               `json:"title"`
+	VideoID           string               `json:"videoId"`
+	PlaylistID        string               `json:"playlistId"`
+	Author            string               `json:"author"`
+	AuthorID          string               `json:"authorId"`
+	AuthorVerified    bool                 `json:"authorVerified"`
+	AuthorThumbnails  []invidiousThumbnail `json:"authorThumbnails"`
+	ChannelHandle     string               `json:"channelHandle"`
+	PlaylistThumbnail string               `json:"playlistThumbnail"`
+	Description       string               `json:"description"`
+	ViewCount         int64                `json:"viewCount"`
+	PublishedText     string               `json:"publishedText"`
+	LengthSeconds     int                  `json:"lengthSeconds"`
+	LiveNow           bool                 `json:"liveNow"`
+	VideoCount        int                  `json:"videoCount"`
+	SubCount          int64                `json:"subCount"`
+	Index             int                  `json:"index"`
+}
+
+type invidiousVideo struct {
+	Title            string               `json:"title"`
+	VideoID          string               `json:"videoId"`
+	Description      string               `json:"description"`
+	Published        int64                `json:"published"`
+	Keywords         []string             `json:"keywords"`
+	Genre            string               `json:"genre"`
+	ViewCount        int64                `json:"viewCount"`
+	LikeCount        int64                `json:"likeCount"`
+	Author           string               `json:"author"`
+	AuthorID         string               `json:"authorId"`
+	AuthorVerified   bool                 `json:"authorVerified"`
+	AuthorThumbnails []invidiousThumbnail `json:"authorThumbnails"`
+	SubCountText     string               `json:"subCountText"`
+	LengthSeconds    int                  `json:"lengthSeconds"`
+	LiveNow          bool                 `json:"liveNow"`
+	IsUpcoming       bool                 `json:"isUpcoming"`
+	VideoThumbnails  []invidiousThumbnail `json:"videoThumbnails"`
+	FormatStreams    []invidiousFormat    `json:"formatStreams"`
+	AdaptiveFormats  []invidiousFormat    `json:"adaptiveFormats"`
+	Captions         []struct {
+		Label        string `json:"label"`
+		LanguageCode string `json:"languageCode"`
+		URL          string `json:"url"`
+	} `json:"captions"`
+}
+
+// SearchWithOptions searches with the filters of opts; links are opened like on the other providers
+func (p *InvidiousProvider) SearchWithOptions(ctx context.Context, input string, opts models.SearchOptions, continuationToken string) (*SearchResponse, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, nil
+	}
+	if resp, ok, err := searchLink(ctx, p, p.client.log(), input, continuationToken); ok {
+		return resp, err
+	}
+
+	ref := continuationToken
+	if ref == "" {
+		q := url.Values{"q": {input}, "page": {"1"}}
+		invidiousSearchFilters(q, opts)
+		ref = "/api/v1/search?" + p.encode(q)
+	}
+
+	p.client.log().Debug("[InvidiousProvider.SearchWithOptions] searching", "ref", ref)
+
+	var items []invidiousItem
+	if err := p.instances.get(ctx, p.client, ref, &items); err != nil {
+		return nil, err
+	}
+
+	next := ""
+	if len(items) > 0 {
+		next = nextPage(ref)
+	}
+	return &SearchResponse{
+		Results:           invidiousResults(items),
+		ContinuationToken: next,
+		HasMore:           next != "",
+	}, nil
+}
+
+// invidiousSearchFilters adds the query parameters of /api/v1/search that match opts
+func invidiousSearchFilters(q url.Values, opts models.SearchOptions) {
+	sorts := map[models.SortOrder]string{models.SortRating: "rating", models.SortDate: "upload_date", models.SortViews: "view_count"}
+	if v, ok := sorts[opts.Sort]; ok {
+		q.Set("sort_by", v)
+	}
+	if opts.Upload != models.UploadAny {
+		q.Set("date", opts.Upload.String())
+	}
+	if opts.Duration != models.DurationAny {
+		q.Set("duration", opts.Duration.String())
+	}
+	if opts.Type != models.TypeAny {
+		q.Set("type", opts.Type.String())
+	}
+
+	var features []string
+	for _, f := range []struct {
+		name string
+		on   bool
+	}{
+		{"hd", opts.Features.HD},
+		{"4k", opts.Features.FourK},
+		{"subtitles", opts.Features.Subtitles},
+		{"creative_commons", opts.Features.CreativeCommons},
+		{"live", opts.Features.Live},
+		{"hdr", opts.Features.HDR},
+	} {
+		if f.on {
+			features = append(features, f.name)
+		}
+	}
+	if len(features) > 0 {
+		q.Set("features", strings.Join(features, ","))
+	}
+}
+
+func (p *InvidiousProvider) GetVideo(ctx context.Context, id string) (*models.Video, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("empty video id")
+	}
+
+	var v invidiousVideo
+	if err := p.instances.get(ctx, p.client, "/api/v1/videos/"+url.PathEscape(id)+"?"+p.encode(nil), &v); err != nil {
+		return nil, err
+	}
+	if v.VideoID == "" {
+		return nil, layoutError(errors.New("videoId missing"))
+	}
+
+	video := &models.Video{
+		ID:              v.VideoID,
+		Title:           v.Title,
+		URL:             youtubeWatchBase + v.VideoID,
+		Description:     v.Description,
+		DurationSeconds: v.LengthSeconds,
+		IsLive:          v.LiveNow,
+		IsUpcoming:      v.IsUpcoming,
+		Keywords:        v.Keywords,
+		Category:        v.Genre,
+		ViewCount:       v.ViewCount,
+		LikeCount:       v.LikeCount,
+		Channel: models.ChannelInfo{
+			ID:              v.AuthorID,
+			Name:            v.Author,
+			URL:             youtubeBase + "/channel/" + v.AuthorID,
+			Thumbnail:       largestInvidiousThumbnail(v.AuthorThumbnails),
+			SubscriberCount: utils.ParseCount(v.SubCountText),
+			Verified:        v.AuthorVerified,
+		},
+	}
+	if v.Published > 0 {
+		video.PublishedAt = time.Unix(v.Published, 0).UTC().Format(time.RFC3339)
+		video.UploadedAt = video.PublishedAt
+	}
+	for _, t := range v.VideoThumbnails {
+		video.Thumbnails = append(video.Thumbnails, models.Thumbnail{URL: absoluteURL(t.URL), Width: t.Width, Height: t.Height})
+	}
+
+	durationMs := int64(v.LengthSeconds) * 1000
+	for _, fm := range v.FormatStreams {
+		video.Formats = append(video.Formats, fm.parse(durationMs))
+	}
+	for _, fm := range v.AdaptiveFormats {
+		video.AdaptiveFormats = append(video.AdaptiveFormats, fm.parse(durationMs))
+	}
+
+	for _, c := range v.Captions {
+		video.Captions = append(video.Captions, models.CaptionTrack{
+			LanguageCode:  c.LanguageCode,
+			LanguageName:  c.Label,
+			URL:           c.URL,
+			AutoGenerated: strings.Contains(strings.ToLower(c.Label), "auto-generated"),
+			Format:        "vtt",
+		})
+	}
+	video.Chapters = parseChapters(nil, video.Description)
+
+	return video, nil
+}
+
+// invidiousFormat is a player response format with some fields renamed
+type invidiousFormat struct {
+	streamFormat
+	Type string `json:"type"`
+	Clen number `json:"clen"`
+	Size string `json:"size"` // "1280x720"
+}
+
+// parse moves the renamed fields back and parses the result like a YouTube format
+func (f invidiousFormat) parse(durationMs int64) models.Format {
+	f.MimeType = f.Type
+	f.ContentLength = f.Clen
+	if w, h, ok := strings.Cut(f.Size, "x"); ok {
+		width, _ := strconv.Atoi(w)
+		height, _ := strconv.Atoi(h)
+		f.Width, f.Height = number(width), number(height)
+	}
+	return parseFormat(&f.streamFormat, "", durationMs)
+}
+
+func (p *InvidiousProvider) GetChannel(ctx context.Context, ref string, tab ChannelTab, continuationToken string) (*ChannelResponse, error) {
+	if tab == "" {
+		tab = ChannelTabVideos
+	}
+
+	if continuationToken != "" {
+		results, next, err := p.channelTab(ctx, continuationToken)
+		if err != nil {
+			return nil, err
+		}
+		return &ChannelResponse{Tab: tab, Results: results, ContinuationToken: next, HasMore: next != ""}, nil
+	}
+
+	path := utils.ChannelPath(ref)
+	if path == "" {
+		return nil, fmt.Errorf("invalid channel reference: %s", ref)
+	}
+	ucid, err := p.channelID(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var info struct {
+		Author           string               `json:"author"`
+		AuthorID         string               `json:"authorId"`
+		AuthorURL        string               `json:"authorUrl"`
+		AuthorThumbnails []invidiousThumbnail `json:"authorThumbnails"`
+		AuthorVerified   bool                 `json:"authorVerified"`
+		SubCount         int64                `json:"subCount"`
+		Description      string               `json:"description"`
+	}
+	if err := p.instances.get(ctx, p.client, "/api/v1/channels/"+ucid+"?"+p.encode(nil), &info); err != nil {
+		return nil, err
+	}
+	if info.AuthorID == "" {
+		return nil, layoutError(errors.New("channel authorId missing"))
+	}
+
+	channel := &models.ChannelInfo{
+		ID:              info.AuthorID,
+		Name:            info.Author,
+		URL:             youtubeBase + "/channel/" + info.AuthorID,
+		Thumbnail:       largestInvidiousThumbnail(info.AuthorThumbnails),
+		Description:     info.Description,
+		SubscriberCount: info.SubCount,
+		Verified:        info.AuthorVerified,
+	}
+	if handle, ok := strings.CutPrefix(info.AuthorURL, "/@"); ok {
+		channel.Handle = "@" + handle
+		channel.URL = youtubeBase + info.AuthorURL
+	}
+
+	results, next, err := p.channelTab(ctx, "/api/v1/channels/"+ucid+"/"+string(tab)+"?"+p.encode(nil))
+	if err != nil {
+		return nil, err
+	}
+	fillChannel(results, channel)
+
+	return &ChannelResponse{
+		Channel:           channel,
+		Tab:               tab,
+		Results:           results,
+		ContinuationToken: next,
+		HasMore:           next != "",
+	}, nil
+}
+
+// channelTab fetches a page of a channel tab, returning the path of the next one
+func (p *InvidiousProvider) channelTab(ctx context.Context, ref string) ([]models.SearchResult, string, error) {
+	var page struct {
+		Videos       []invidiousItem `json:"videos"`
+		Playlists    []invidiousItem `json:"playlists"`
+		Continuation string          `json:"continuation"`
+	}
+	if err := p.instances.get(ctx, p.client, ref, &page); err != nil {
+		return nil, "", err
+	}
+
+	results := invidiousResults(append(page.Videos, page.Playlists...))
+	if page.Continuation == "" {
+		return results, "", nil
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, "", err
+	}
+	q := u.Query()
+	q.Set("continuation", page.Continuation)
+	return results, u.Path + "?" + q.Encode(), nil
+}
+
+// channelID resolves @handles and custom URLs through /api/v1/resolveurl
+func (p *InvidiousProvider) channelID(ctx context.Context, path string) (string, error) {
+	if id, ok := strings.CutPrefix(path, "/channel/"); ok {
+		return id, nil
+	}
+
+	var resolved struct {
+		UCID string `json:"ucid"`
+	}
+	if err := p.instances.get(ctx, p.client, "/api/v1/resolveurl?"+url.Values{"url": {youtubeBase + path}}.Encode(), &resolved); err != nil {
+		return "", err
+	}
+	if resolved.UCID == "" {
+		return "", fmt.Errorf("%s does not resolve to a channel", path)
+	}
+	return resolved.UCID, nil
+}
+
+func (p *InvidiousProvider) GetPlaylist(ctx context.Context, id string, continuationToken string) (*PlaylistResponse, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("empty playlist id")
+	}
+
+	ref := continuationToken
+	if ref == "" {
+		ref = "/api/v1/playlists/" + url.PathEscape(id) + "?" + p.encode(url.Values{"page": {"1"}})
+	}
+
+	var page struct {
+		Title             string          `json:"title"`
+		PlaylistID        string          `json:"playlistId"`
+		Author            string          `json:"author"`
+		AuthorID          string          `json:"authorId"`
+		Description       string          `json:"description"`
+		PlaylistThumbnail string          `json:"playlistThumbnail"`
+		VideoCount        int             `json:"videoCount"`
+		ViewCount         int64           `json:"viewCount"`
+		Videos            []invidiousItem `json:"videos"`
+	}
+	if err := p.instances.get(ctx, p.client, ref, &page); err != nil {
+		return nil, err
+	}
+
+	for i := range page.Videos {
+		page.Videos[i].Type = "video"
+	}
+	results := invidiousResults(page.Videos)
+
+	// pages hold a fixed number of videos, the index of the last one tells whether more follow
+	next := ""
+	if n := len(page.Videos); n > 0 && page.Videos[n-1].Index+1 < page.VideoCount {
+		next = nextPage(ref)
+	}
+
+	resp := &PlaylistResponse{Results: results, ContinuationToken: next, HasMore: next != ""}
+	if continuationToken == "" {
+		resp.Playlist = &models.Playlist{
+			ID:          id,
+			Title:       page.Title,
+			Description: page.Description,
+			URL:         youtubePlaylistBase + id,
+			Thumbnail:   absoluteURL(page.PlaylistThumbnail),
+			OwnerName:   page.Author,
+			OwnerID:     page.AuthorID,
+			VideoCount:  page.VideoCount,
+			ViewCount:   page.ViewCount,
+		}
+	}
+	return resp, nil
+}
+
+func (p *InvidiousProvider) GetComments(ctx context.Context, videoID string, continuationToken string) (*CommentsResponse, error) {
+	q := url.Values{}
+	if continuationToken != "" {
+		q.Set("continuation", continuationToken)
+	}
+
+	var page struct {
+		Comments []struct {
+			CommentID            string `json:"commentId"`
+			Author               string `json:"author"`
+			AuthorID             string `json:"authorId"`
+			Content              string `json:"content"`
+			PublishedText        string `json:"publishedText"`
+			LikeCount            int64  `json:"likeCount"`
+			IsPinned             bool   `json:"isPinned"`
+			AuthorIsChannelOwner bool   `json:"authorIsChannelOwner"`
+			Replies              struct {
+				ReplyCount int `json:"replyCount"`
+			} `json:"replies"`
+		} `json:"comments"`
+		Continuation string `json:"continuation"`
+	}
+	if err := p.instances.get(ctx, p.client, "/api/v1/comments/"+url.PathEscape(videoID)+"?"+p.encode(q), &page); err != nil {
+		return nil, err
+	}
+
+	resp := &CommentsResponse{ContinuationToken: page.Continuation, HasMore: page.Continuation != ""}
+	for _, c := range page.Comments {
+		resp.Comments = append(resp.Comments, models.Comment{
+			ID:         c.CommentID,
+			Author:     c.Author,
+			AuthorID:   c.AuthorID,
+			Text:       c.Content,
+			Published:  c.PublishedText,
+			LikeCount:  c.LikeCount,
+			ReplyCount: c.Replies.ReplyCount,
+			Pinned:     c.IsPinned,
+			ByUploader: c.AuthorIsChannelOwner,
+		})
+	}
+	return resp, nil
+}
+
+// FetchCaptionCues downloads a WebVTT track from the instance that listed it, or any other
+func (p *InvidiousProvider) FetchCaptionCues(ctx context.Context, track models.CaptionTrack) ([]models.CaptionCue, error) {
+	if track.URL == "" {
+		return nil, errors.New("caption track has no URL")
+	}
+	body, err := p.instances.getRaw(ctx, p.client, track.URL)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCaptions(string(body))
+}
+
+// encode adds the locale of the client to q
+func (p *InvidiousProvider) encode(q url.Values) string {
+	if q == nil {
+		q = url.Values{}
+	}
+	if p.client.Language != "" {
+		q.Set("hl", p.client.Language)
+	}
+	if p.client.Region != "" {
+		q.Set("region", p.client.Region)
+	}
+	return q.Encode()
+}
+
+func invidiousResults(items []invidiousItem) []models.SearchResult {
+	var results []models.SearchResult
+	for _, it := range items {
+		switch {
+		case (it.Type == "video" || it.Type == "") && it.VideoID != "":
+			duration := ""
+			if it.LengthSeconds > 0 {
+				duration = utils.FormatDuration(it.LengthSeconds)
+			}
+			results = append(results, models.SearchResult{
+				ID:           it.VideoID,
+				Title:        it.Title,
+				URL:          youtubeWatchBase + it.VideoID,
+				Thumbnail:    "https://i.ytimg.com/vi/" + it.VideoID + "/hqdefault.jpg",
+				Duration:     duration,
+				DurationSec:  it.LengthSeconds,
+				ChannelName:  it.Author,
+				ChannelID:    it.AuthorID,
+				ChannelURl:   channelURL(it.AuthorID),
+				IsLive:       it.LiveNow,
+				ViewCount:    it.ViewCount,
+				Published:    it.PublishedText,
+				PublishedAge: utils.ParseRelativeTime(it.PublishedText),
+				Snippet:      it.Description,
+				Verified:     it.AuthorVerified,
+			})
+		case it.Type == "playlist" && it.PlaylistID != "":
+			results = append(results, models.SearchResult{
+				Kind:        models.ResultPlaylist,
+				ID:          it.PlaylistID,
+				Title:       it.Title,
+				URL:         youtubePlaylistBase + it.PlaylistID,
+				Thumbnail:   absoluteURL(it.PlaylistThumbnail),
+				ChannelName: it.Author,
+				ChannelID:   it.AuthorID,
+				ChannelURl:  channelURL(it.AuthorID),
+				VideoCount:  it.VideoCount,
+				Verified:    it.AuthorVerified,
+			})
+		case it.Type == "channel" && it.AuthorID != "":
+			results = append(results, models.SearchResult{
+				Kind:        models.ResultChannel,
+				ID:          it.AuthorID,
+				Title:       it.Author,
+				URL:         channelURL(it.AuthorID),
+				Thumbnail:   largestInvidiousThumbnail(it.AuthorThumbnails),
+				ChannelName: it.ChannelHandle,
+				ChannelID:   it.AuthorID,
+				ChannelURl:  channelURL(it.AuthorID),
+				Subscribers: it.SubCount,
+				Snippet:     it.Description,
+				Verified:    it.AuthorVerified,
+			})
+		}
+	}
+	return results
+}
+
+func largestInvidiousThumbnail(thumbs []invidiousThumbnail) string {
+	best := invidiousThumbnail{}
+	for _, t := range thumbs {
+		if t.Width >= best.Width {
+			best = t
+		}
+	}
+	return absoluteURL(best.URL)
+}
+
+// nextPage increments the page parameter of ref
+func nextPage(ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	q.Set("page", strconv.Itoa(max(page, 1)+1))
+	return u.Path + "?" + q.Encode()
+}
+
+func channelURL(id string) string {
+	if id == "" {
+		return ""
+	}
+	return youtubeBase + "/channel/" + id
+}
+
+// absoluteURL completes the protocol relative image URLs the REST backends hand out
+func absoluteURL(u string) string {
+	if strings.HasPrefix(u, "//") {
+		return "https:" + u
+	}
+	return u
+}
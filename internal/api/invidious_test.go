@@ -0,0 +1,293 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// newRESTServer answers each path in routes with its JSON body, and everything else with a 404
+func newRESTServer(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error":"not found"}`)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newRESTClient(srv *httptest.Server) *Client {
+	c := NewClient()
+	c.HTTPClient = srv.Client()
+	return c
+}
+
+func TestInvidiousSearch(t *testing.T) {
+	var query atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query.Store(r.URL.RawQuery)
+		io.WriteString(w, `[
+			{"type":"video","title":"Concurrency is not parallelism","videoId":"oV9rvDllKEg","author":"gnbitcom","authorId":"UCarZKD-Bf3zD2GRTqvBBqiw","authorVerified":true,"viewCount":912345,"publishedText":"11 years ago","lengthSeconds":1900,"description":"Rob Pike"},
+			{"type":"channel","author":"The Go Programming Language","authorId":"UC_BzFbxG2za3bp5NRRRXJSw","authorThumbnails":[{"url":"//yt3.ggpht.com/small","width":32,"height":32},{"url":"//yt3.ggpht.com/large","width":176,"height":176}],"subCount":160000,"videoCount":400,"channelHandle":"@golang"},
+			{"type":"playlist","title":"GopherCon 2023","playlistId":"PL2ntRZ1ySWBdD9bru6IR-_WXUgJqvrtx9","author":"Gopher Academy","authorId":"UCx9QVEApa5BKLw9r8cnOFEA","videoCount":42,"playlistThumbnail":"https://i.ytimg.com/vi/x/hqdefault.jpg"}
+		]`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newRESTClient(srv)
+	c.SetLocale("pt", "BR")
+	p := NewInvidiousProvider(c, []string{srv.URL + "/"})
+
+	resp, err := p.SearchWithOptions(t.Context(), fixtureQuery, models.SearchOptions{Sort: models.SortDate, Type: models.TypeVideo}, "")
+	if err != nil {
+		t.Fatalf("SearchWithOptions: %v", err)
+	}
+	for _, want := range []string{"sort_by=upload_date", "type=video", "hl=pt", "region=BR", "page=1"} {
+		if q := query.Load().(string); !strings.Contains(q, want) {
+			t.Errorf("query %q lacks %s", q, want)
+		}
+	}
+
+	if len(resp.Results) != 3 {
+		t.Fatalf("got %d results, want 3", len(resp.Results))
+	}
+	video, channel, playlist := resp.Results[0], resp.Results[1], resp.Results[2]
+	if video.ID != "oV9rvDllKEg" || video.DurationSec != 1900 || video.ViewCount != 912345 || !video.Verified {
+		t.Errorf("video = %+v", video)
+	}
+	if channel.Kind != models.ResultChannel || channel.ID != "UC_BzFbxG2za3bp5NRRRXJSw" || channel.Thumbnail != "https://yt3.ggpht.com/large" {
+		t.Errorf("channel = %+v", channel)
+	}
+	if playlist.Kind != models.ResultPlaylist || playlist.VideoCount != 42 {
+		t.Errorf("playlist = %+v", playlist)
+	}
+
+	if !resp.HasMore || !strings.Contains(resp.ContinuationToken, "page=2") {
+		t.Fatalf("continuation %q should ask for page 2", resp.ContinuationToken)
+	}
+	if _, err := p.SearchWithOptions(t.Context(), fixtureQuery, models.SearchOptions{}, resp.ContinuationToken); err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if q := query.Load().(string); !strings.Contains(q, "page=2") || !strings.Contains(q, "sort_by=upload_date") {
+		t.Errorf("second page query %q lost the filters or the page", q)
+	}
+}
+
+func TestInvidiousVideo(t *testing.T) {
+	srv := newRESTServer(t, map[string]string{
+		"/api/v1/videos/" + fixtureVideoID: `{
+			"title":"Go Concurrency Patterns","videoId":"f6kdp27TYZs","description":"0:00 Intro\n5:10 Generators",
+			"published":1341187200,"keywords":["go"],"genre":"Education","viewCount":1000,"likeCount":20,
+			"author":"Google for Developers","authorId":"UC_x5XG1OV2P6uZZ5FSM9Ttw","subCountText":"2.4M","lengthSeconds":3060,
+			"formatStreams":[{"itag":"18","type":"video/mp4; codecs=\"avc1.42001E, mp4a.40.2\"","url":"https://inv.example/latest_version?id=x&itag=18","qualityLabel":"360p","size":"640x360","bitrate":"500000"}],
+			"adaptiveFormats":[{"itag":"140","type":"audio/mp4; codecs=\"mp4a.40.2\"","url":"https://inv.example/latest_version?id=x&itag=140","clen":"4900000","bitrate":"130000","audioQuality":"AUDIO_QUALITY_MEDIUM"}],
+			"captions":[{"label":"English (auto-generated)","languageCode":"en","url":"/api/v1/captions/f6kdp27TYZs?label=English+%28auto-generated%29"}]
+		}`,
+		"/api/v1/captions/" + fixtureVideoID: "WEBVTT\nKind: captions\n\n00:00:01.000 --> 00:00:02.500 align:start position:0%\nhello &amp; <c>welcome</c>\n\n01:02.000 --> 01:03.250\nsecond\nline\n",
+	})
+	p := NewInvidiousProvider(newRESTClient(srv), []string{srv.URL})
+
+	video, err := p.GetVideo(t.Context(), fixtureVideoID)
+	if err != nil {
+		t.Fatalf("GetVideo: %v", err)
+	}
+	if video.Title != "Go Concurrency Patterns" || video.Channel.SubscriberCount != 2_400_000 || video.PublishedAt != "2012-07-02T00:00:00Z" {
+		t.Errorf("video = %+v", video)
+	}
+	if len(video.Chapters) != 2 || video.Chapters[1].StartSec != 310 {
+		t.Errorf("chapters = %+v", video.Chapters)
+	}
+	if len(video.Formats) != 1 || video.Formats[0].Width != 640 || video.Formats[0].QualityLabel != "360p" {
+		t.Errorf("formats = %+v", video.Formats)
+	}
+	if len(video.AdaptiveFormats) != 1 || !video.AdaptiveFormats[0].IsAudioOnly || video.AdaptiveFormats[0].ContentLength != 4_900_000 {
+		t.Errorf("adaptive formats = %+v", video.AdaptiveFormats)
+	}
+	if len(video.Captions) != 1 || !video.Captions[0].AutoGenerated {
+		t.Fatalf("captions = %+v", video.Captions)
+	}
+
+	cues, err := p.FetchCaptionCues(t.Context(), video.Captions[0])
+	if err != nil {
+		t.Fatalf("FetchCaptionCues: %v", err)
+	}
+	want := []models.CaptionCue{
+		{StartMs: 1000, EndMs: 2500, Text: "hello & welcome"},
+		{StartMs: 62000, EndMs: 63250, Text: "second\nline"},
+	}
+	if len(cues) != len(want) {
+		t.Fatalf("cues = %+v, want %+v", cues, want)
+	}
+	for i := range want {
+		if cues[i] != want[i] {
+			t.Errorf("cue %d = %+v, want %+v", i, cues[i], want[i])
+		}
+	}
+}
+
+func TestInvidiousComments(t *testing.T) {
+	srv := newRESTServer(t, map[string]string{
+		"/api/v1/comments/" + fixtureVideoID: `{"comments":[
+			{"commentId":"Ugz1","author":"@gopher","authorId":"UCgopher","content":"great talk","publishedText":"2 years ago","likeCount":31,"isPinned":true,"authorIsChannelOwner":false,"replies":{"replyCount":4}}
+		],"continuation":"Eg0SC2Y2a2RwMjdUWVpz"}`,
+	})
+	p := NewInvidiousProvider(newRESTClient(srv), []string{srv.URL})
+
+	resp, err := p.GetComments(t.Context(), fixtureVideoID, "")
+	if err != nil {
+		t.Fatalf("GetComments: %v", err)
+	}
+	want := models.Comment{ID: "Ugz1", Author: "@gopher", AuthorID: "UCgopher", Text: "great talk", Published: "2 years ago", LikeCount: 31, ReplyCount: 4, Pinned: true}
+	if len(resp.Comments) != 1 || resp.Comments[0] != want {
+		t.Errorf("comments = %+v, want %+v", resp.Comments, want)
+	}
+	if !resp.HasMore || resp.ContinuationToken != "Eg0SC2Y2a2RwMjdUWVpz" {
+		t.Errorf("continuation = %q", resp.ContinuationToken)
+	}
+}
+
+func TestInstanceFailover(t *testing.T) {
+	var down atomic.Int32
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		down.Add(1)
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	t.Cleanup(broken.Close)
+	working := newRESTServer(t, map[string]string{
+		"/api/v1/comments/" + fixtureVideoID: `{"comments":[]}`,
+	})
+
+	// a plain client, the retry transport would ask the broken instance again before the pool sees the error
+	c := newRESTClient(working)
+	p := NewInvidiousProvider(c, []string{broken.URL, working.URL})
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.GetComments(t.Context(), fixtureVideoID, ""); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if down.Load() != 1 {
+		t.Errorf("broken instance was asked %d times, want once before the pool moved on", down.Load())
+	}
+}
+
+func TestInstanceFailoverStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"rate limited", http.StatusTooManyRequests, `{"error":"Service unavailable, try again later"}`},
+		{"overloaded", http.StatusServiceUnavailable, `{"error":"Video unavailable"}`},
+		{"private on a broken instance", http.StatusInternalServerError, `{"error":"This video is private"}`},
+		{"proxy 404", http.StatusNotFound, `<html><body>404 Not Found</body></html>`},
+		{"empty 404", http.StatusNotFound, ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			t.Cleanup(first.Close)
+			working := newRESTServer(t, map[string]string{
+				"/api/v1/comments/" + fixtureVideoID: `{"comments":[]}`,
+			})
+
+			p := NewInvidiousProvider(newRESTClient(working), []string{first.URL, working.URL})
+			if _, err := p.GetComments(t.Context(), fixtureVideoID, ""); err != nil {
+				t.Errorf("next instance was not tried: %v", err)
+			}
+		})
+	}
+}
+
+func TestInstanceErrorKeepsReason(t *testing.T) {
+	// every instance fails the same way, the reason survives the status
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, `{"error":"This video is private"}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewInvidiousProvider(newRESTClient(srv), []string{srv.URL, srv.URL})
+	_, err := p.GetVideo(t.Context(), fixtureVideoID)
+	var httpErr *utils.HTTPError
+	if !errors.Is(err, ErrPrivate) || !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("want ErrPrivate wrapping the 500, got %v", err)
+	}
+}
+
+func TestInstanceNoFailover(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"private", http.StatusForbidden, `{"error":"This video is private"}`, ErrPrivate},
+		{"not found", http.StatusNotFound, `{"error":"Video unavailable"}`, ErrVideoUnavailable},
+		{"bad request", http.StatusBadRequest, `{"error":"Invalid video id"}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var second atomic.Int32
+			first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			t.Cleanup(first.Close)
+			other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				second.Add(1)
+			}))
+			t.Cleanup(other.Close)
+
+			p := NewInvidiousProvider(newRESTClient(first), []string{first.URL, other.URL})
+			_, err := p.GetVideo(t.Context(), fixtureVideoID)
+			if err == nil {
+				t.Fatal("want an error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("want %v, got %v", tt.want, err)
+			}
+			if second.Load() != 0 {
+				t.Errorf("%v was retried on the next instance", err)
+			}
+		})
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	c := NewClient()
+	if p, err := NewProvider("", nil, c); err != nil || p != Provider(c) {
+		t.Errorf("default backend = %v, %v; want the client", p, err)
+	}
+	if _, err := NewProvider("piped", nil, c); err == nil {
+		t.Error("piped without instances should fail")
+	}
+	if _, err := NewProvider("vimeo", []string{"https://example.org"}, c); err == nil {
+		t.Error("unknown backend should fail")
+	}
+	for _, backend := range Backends {
+		p, err := NewProvider(backend, []string{"https://example.org"}, c)
+		if err != nil || p.Name() != backend {
+			t.Errorf("NewProvider(%s) = %v, %v", backend, p, err)
+		}
+	}
+}
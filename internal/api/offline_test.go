@@ -0,0 +1,44 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Drack112/go-youtube/pkg/httpx"
+)
+
+// TestProvidersOffline answers the REST backends from a cache filled by an earlier online run
+func TestProvidersOffline(t *testing.T) {
+	srv := newRESTServer(t, map[string]string{
+		"/api/v1/videos/" + fixtureVideoID: `{"title":"Go Concurrency Patterns","videoId":"f6kdp27TYZs","author":"Google for Developers","authorId":"UC_x5XG1OV2P6uZZ5FSM9Ttw","lengthSeconds":3060}`,
+		"/streams/" + fixtureVideoID:       `{"title":"Go Concurrency Patterns","uploader":"Google for Developers","uploaderUrl":"/channel/UC_x5XG1OV2P6uZZ5FSM9Ttw","duration":3060}`,
+	})
+	dir := t.TempDir()
+
+	client := func(offline bool) *Client {
+		c := NewClient()
+		c.HTTPClient = &http.Client{Transport: httpx.NewCache(srv.Client().Transport, httpx.CacheOptions{Dir: dir, TTL: httpx.DefaultTTL, Offline: offline})}
+		return c
+	}
+	providers := func(c *Client) []Provider {
+		return []Provider{NewInvidiousProvider(c, []string{srv.URL}), NewPipedProvider(c, []string{srv.URL})}
+	}
+
+	for _, p := range providers(client(false)) {
+		if _, err := p.GetVideo(t.Context(), fixtureVideoID); err != nil {
+			t.Fatalf("%s online: %v", p.Name(), err)
+		}
+	}
+	srv.Close()
+
+	for _, p := range providers(client(true)) {
+		video, err := p.GetVideo(t.Context(), fixtureVideoID)
+		if err != nil || video.Title != "Go Concurrency Patterns" {
+			t.Errorf("%s offline: %+v, %v", p.Name(), video, err)
+		}
+		if _, err := p.GetVideo(t.Context(), fixtureLiveID); !errors.Is(err, httpx.ErrOffline) {
+			t.Errorf("%s offline, uncached video: want ErrOffline, got %v", p.Name(), err)
+		}
+	}
+}
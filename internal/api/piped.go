@@ -0,0 +1,554 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// PipedProvider reads content from the REST API of Piped instances (the API host, not the frontend).
+// Its continuation tokens are the path of the next request, valid on any instance.
+type PipedProvider struct {
+	client    *Client
+	instances *instancePool
+}
+
+// NewPipedProvider sends requests through c to the first of instances that answers
+func NewPipedProvider(c *Client, instances []string) *PipedProvider {
+	return &PipedProvider{client: c, instances: newInstancePool("piped", instances)}
+}
+
+func (p *PipedProvider) Name() string {
+	return "piped"
+}
+
+type pipedItem struct {
+	URL              string `json:"url"`
+	Type             string `json:"type"`
+	Title            string `json:"title"`
+	Name             string `json:"name"`
+	Thumbnail        string `json:"thumbnail"`
+	UploaderName     string `json:"uploaderName"`
+	UploaderURL      string `json:"uploaderUrl"`
+	UploaderVerified bool   `json:"uploaderVerified"`
+	UploadedDate     string `json:"uploadedDate"`
+	ShortDescription string `json:"shortDescription"`
+	Description      string `json:"description"`
+	Duration         int    `json:"duration"`
+	Views            int64  `json:"views"`
+	IsShort          bool   `json:"isShort"`
+	Subscribers      int64  `json:"subscribers"`
+	Videos           int    `json:"videos"`
+	Verified         bool   `json:"verified"`
+}
+
+type pipedStream struct {
+	URL           string `json:"url"`
+	Quality       string `json:"quality"`
+	MimeType      string `json:"mimeType"`
+	Codec         string `json:"codec"`
+	Itag          int    `json:"itag"`
+	Bitrate       int    `json:"bitrate"`
+	ContentLength int64  `json:"contentLength"`
+	VideoOnly     bool   `json:"videoOnly"`
+	Width         int    `json:"width"`
+	Height        int    `json:"height"`
+	FPS           int    `json:"fps"`
+}
+
+type pipedVideo struct {
+	Title                   string        `json:"title"`
+	Description             string        `json:"description"`
+	UploadDate              string        `json:"uploadDate"`
+	Uploader                string        `json:"uploader"`
+	UploaderURL             string        `json:"uploaderUrl"`
+	UploaderAvatar          string        `json:"uploaderAvatar"`
+	UploaderVerified        bool          `json:"uploaderVerified"`
+	UploaderSubscriberCount int64         `json:"uploaderSubscriberCount"`
+	ThumbnailURL            string        `json:"thumbnailUrl"`
+	Duration                int           `json:"duration"`
+	Views                   int64         `json:"views"`
+	Likes                   int64         `json:"likes"`
+	Livestream              bool          `json:"livestream"`
+	Category                string        `json:"category"`
+	Tags                    []string      `json:"tags"`
+	AudioStreams            []pipedStream `json:"audioStreams"`
+	VideoStreams            []pipedStream `json:"videoStreams"`
+	Subtitles               []struct {
+		URL           string `json:"url"`
+		Name          string `json:"name"`
+		Code          string `json:"code"`
+		AutoGenerated bool   `json:"autoGenerated"`
+	} `json:"subtitles"`
+	Chapters []struct {
+		Title string `json:"title"`
+		Image string `json:"image"`
+		Start int    `json:"start"`
+	} `json:"chapters"`
+}
+
+// SearchWithOptions searches for input. Piped only filters by result type, other filters are ignored.
+func (p *PipedProvider) SearchWithOptions(ctx context.Context, input string, opts models.SearchOptions, continuationToken string) (*SearchResponse, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, nil
+	}
+	if resp, ok, err := searchLink(ctx, p, p.client.log(), input, continuationToken); ok {
+		return resp, err
+	}
+
+	ref := continuationToken
+	if ref == "" {
+		filters := map[models.TypeFilter]string{models.TypeVideo: "videos", models.TypeChannel: "channels", models.TypePlaylist: "playlists"}
+		filter, ok := filters[opts.Type]
+		if !ok {
+			filter = "all"
+		}
+		if opts.Type == models.TypeMovie || opts.Sort != models.SortRelevance || opts.Upload != models.UploadAny ||
+			opts.Duration != models.DurationAny || opts.Features != (models.SearchFeatures{}) {
+			p.client.log().Warn("[PipedProvider.SearchWithOptions] piped only filters by result type, ignoring the other filters")
+		}
+		ref = "/search?" + url.Values{"q": {input}, "filter": {filter}}.Encode()
+	}
+
+	var page struct {
+		Items    []pipedItem `json:"items"`
+		Nextpage string      `json:"nextpage"`
+	}
+	if err := p.instances.get(ctx, p.client, ref, &page); err != nil {
+		return nil, err
+	}
+
+	next := pipedNextPage(ref, "/nextpage/search", page.Nextpage)
+	return &SearchResponse{Results: pipedResults(page.Items), ContinuationToken: next, HasMore: next != ""}, nil
+}
+
+func (p *PipedProvider) GetVideo(ctx context.Context, id string) (*models.Video, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("empty video id")
+	}
+
+	var v pipedVideo
+	if err := p.instances.get(ctx, p.client, "/streams/"+url.PathEscape(id), &v); err != nil {
+		return nil, err
+	}
+	if v.Title == "" {
+		return nil, layoutError(errors.New("stream title missing"))
+	}
+
+	channelID := pipedID(v.UploaderURL)
+	video := &models.Video{
+		ID:              id,
+		Title:           v.Title,
+		URL:             youtubeWatchBase + id,
+		Description:     htmlToText(v.Description),
+		DurationSeconds: v.Duration,
+		IsLive:          v.Livestream,
+		UploadedAt:      v.UploadDate,
+		PublishedAt:     v.UploadDate,
+		Keywords:        v.Tags,
+		Category:        v.Category,
+		ViewCount:       v.Views,
+		LikeCount:       v.Likes,
+		Channel: models.ChannelInfo{
+			ID:              channelID,
+			Name:            v.Uploader,
+			URL:             channelURL(channelID),
+			Thumbnail:       v.UploaderAvatar,
+			SubscriberCount: v.UploaderSubscriberCount,
+			Verified:        v.UploaderVerified,
+		},
+	}
+	if v.ThumbnailURL != "" {
+		video.Thumbnails = []models.Thumbnail{{URL: v.ThumbnailURL}}
+	}
+
+	for _, s := range v.VideoStreams {
+		if s.VideoOnly {
+			video.AdaptiveFormats = append(video.AdaptiveFormats, pipedFormat(s))
+		} else {
+			video.Formats = append(video.Formats, pipedFormat(s))
+		}
+	}
+	for _, s := range v.AudioStreams {
+		video.AdaptiveFormats = append(video.AdaptiveFormats, pipedFormat(s))
+	}
+
+	for _, s := range v.Subtitles {
+		video.Captions = append(video.Captions, models.CaptionTrack{
+			LanguageCode:  s.Code,
+			LanguageName:  s.Name,
+			URL:           s.URL,
+			AutoGenerated: s.AutoGenerated,
+			Format:        "vtt",
+		})
+	}
+
+	for _, c := range v.Chapters {
+		video.Chapters = append(video.Chapters, models.Chapter{Title: c.Title, StartSec: c.Start, Thumbnail: c.Image})
+	}
+	if len(video.Chapters) == 0 {
+		video.Chapters = parseChapters(nil, video.Description)
+	}
+
+	return video, nil
+}
+
+func pipedFormat(s pipedStream) models.Format {
+	format := models.Format{
+		Itag:          s.Itag,
+		MimeType:      s.MimeType,
+		Codecs:        s.Codec,
+		Bitrate:       s.Bitrate,
+		Width:         s.Width,
+		Height:        s.Height,
+		FPS:           s.FPS,
+		ContentLength: s.ContentLength,
+		IsAudioOnly:   strings.HasPrefix(s.MimeType, "audio/"),
+		IsVideoOnly:   s.VideoOnly,
+		URL:           s.URL,
+	}
+	if !format.IsAudioOnly {
+		format.QualityLabel = s.Quality
+		format.IsHDR = strings.Contains(s.Quality, "HDR")
+	}
+	return format
+}
+
+// pipedChannelTabs are the names Piped gives the tabs other than videos
+var pipedChannelTabs = map[ChannelTab]string{
+	ChannelTabShorts:    "shorts",
+	ChannelTabLive:      "livestreams",
+	ChannelTabPlaylists: "playlists",
+}
+
+func (p *PipedProvider) GetChannel(ctx context.Context, ref string, tab ChannelTab, continuationToken string) (*ChannelResponse, error) {
+	if tab == "" {
+		tab = ChannelTabVideos
+	}
+
+	if continuationToken != "" {
+		results, next, err := p.channelPage(ctx, continuationToken)
+		if err != nil {
+			return nil, err
+		}
+		return &ChannelResponse{Tab: tab, Results: results, ContinuationToken: next, HasMore: next != ""}, nil
+	}
+
+	path := utils.ChannelPath(ref)
+	if path == "" {
+		return nil, fmt.Errorf("invalid channel reference: %s", ref)
+	}
+	// Piped looks handles up like custom URLs
+	if strings.HasPrefix(path, "/@") {
+		path = "/c" + path
+	}
+
+	var page struct {
+		ID              string      `json:"id"`
+		Name            string      `json:"name"`
+		AvatarURL       string      `json:"avatarUrl"`
+		Description     string      `json:"description"`
+		SubscriberCount int64       `json:"subscriberCount"`
+		Verified        bool        `json:"verified"`
+		Nextpage        string      `json:"nextpage"`
+		RelatedStreams  []pipedItem `json:"relatedStreams"`
+		Tabs            []struct {
+			Name string `json:"name"`
+			Data string `json:"data"`
+		} `json:"tabs"`
+	}
+	if err := p.instances.get(ctx, p.client, path, &page); err != nil {
+		return nil, err
+	}
+	if page.ID == "" {
+		return nil, layoutError(errors.New("channel id missing"))
+	}
+
+	channel := &models.ChannelInfo{
+		ID:              page.ID,
+		Name:            page.Name,
+		URL:             channelURL(page.ID),
+		Thumbnail:       page.AvatarURL,
+		Description:     page.Description,
+		SubscriberCount: page.SubscriberCount,
+		Verified:        page.Verified,
+	}
+	if handle, ok := strings.CutPrefix(path, "/c/@"); ok {
+		channel.Handle = "@" + handle
+		channel.URL = youtubeBase + "/@" + handle
+	}
+
+	var results []models.SearchResult
+	next := ""
+	if tab == ChannelTabVideos {
+		results = pipedResults(page.RelatedStreams)
+		if page.Nextpage != "" {
+			next = "/nextpage/channel/" + page.ID + "?" + url.Values{"nextpage": {page.Nextpage}}.Encode()
+		}
+	} else {
+		data := ""
+		for _, t := range page.Tabs {
+			if t.Name == pipedChannelTabs[tab] {
+				data = t.Data
+			}
+		}
+		// channels without shorts or streams simply have no such tab
+		if data != "" {
+			var err error
+			results, next, err = p.channelPage(ctx, "/channels/tabs?"+url.Values{"data": {data}}.Encode())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	fillChannel(results, channel)
+
+	return &ChannelResponse{
+		Channel:           channel,
+		Tab:               tab,
+		Results:           results,
+		ContinuationToken: next,
+		HasMore:           next != "",
+	}, nil
+}
+
+// channelPage fetches a page of a channel tab or the next page of its videos. Tabs list their items
+// under content, the videos continuation under relatedStreams.
+func (p *PipedProvider) channelPage(ctx context.Context, ref string) ([]models.SearchResult, string, error) {
+	var page struct {
+		Content        []pipedItem `json:"content"`
+		RelatedStreams []pipedItem `json:"relatedStreams"`
+		Nextpage       string      `json:"nextpage"`
+	}
+	if err := p.instances.get(ctx, p.client, ref, &page); err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if page.Nextpage != "" {
+		u, err := url.Parse(ref)
+		if err != nil {
+			return nil, "", err
+		}
+		q := u.Query()
+		q.Set("nextpage", page.Nextpage)
+		next = u.Path + "?" + q.Encode()
+	}
+	return pipedResults(append(page.Content, page.RelatedStreams...)), next, nil
+}
+
+func (p *PipedProvider) GetPlaylist(ctx context.Context, id string, continuationToken string) (*PlaylistResponse, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("empty playlist id")
+	}
+
+	ref := continuationToken
+	if ref == "" {
+		ref = "/playlists/" + url.PathEscape(id)
+	}
+
+	var page struct {
+		Name           string      `json:"name"`
+		ThumbnailURL   string      `json:"thumbnailUrl"`
+		Description    string      `json:"description"`
+		Uploader       string      `json:"uploader"`
+		UploaderURL    string      `json:"uploaderUrl"`
+		Videos         int         `json:"videos"`
+		Nextpage       string      `json:"nextpage"`
+		RelatedStreams []pipedItem `json:"relatedStreams"`
+	}
+	if err := p.instances.get(ctx, p.client, ref, &page); err != nil {
+		return nil, err
+	}
+
+	next := ""
+	if page.Nextpage != "" {
+		next = "/nextpage/playlists/" + url.PathEscape(id) + "?" + url.Values{"nextpage": {page.Nextpage}}.Encode()
+	}
+	resp := &PlaylistResponse{Results: pipedResults(page.RelatedStreams), ContinuationToken: next, HasMore: next != ""}
+	if continuationToken == "" {
+		resp.Playlist = &models.Playlist{
+			ID:          id,
+			Title:       page.Name,
+			Description: htmlToText(page.Description),
+			URL:         youtubePlaylistBase + id,
+			Thumbnail:   page.ThumbnailURL,
+			OwnerName:   page.Uploader,
+			OwnerID:     pipedID(page.UploaderURL),
+			VideoCount:  page.Videos,
+		}
+	}
+	return resp, nil
+}
+
+func (p *PipedProvider) GetComments(ctx context.Context, videoID string, continuationToken string) (*CommentsResponse, error) {
+	ref := continuationToken
+	if ref == "" {
+		ref = "/comments/" + url.PathEscape(videoID)
+	}
+
+	var page struct {
+		Comments []struct {
+			CommentID     string `json:"commentId"`
+			Author        string `json:"author"`
+			CommentorURL  string `json:"commentorUrl"`
+			CommentText   string `json:"commentText"`
+			CommentedTime string `json:"commentedTime"`
+			LikeCount     int64  `json:"likeCount"`
+			ReplyCount    int    `json:"replyCount"`
+			Pinned        bool   `json:"pinned"`
+			ChannelOwner  bool   `json:"channelOwner"`
+		} `json:"comments"`
+		Nextpage string `json:"nextpage"`
+		Disabled bool   `json:"disabled"`
+	}
+	if err := p.instances.get(ctx, p.client, ref, &page); err != nil {
+		return nil, err
+	}
+	if page.Disabled {
+		return nil, ErrCommentsDisabled
+	}
+
+	next := ""
+	if page.Nextpage != "" {
+		next = "/nextpage/comments/" + url.PathEscape(videoID) + "?" + url.Values{"nextpage": {page.Nextpage}}.Encode()
+	}
+	resp := &CommentsResponse{ContinuationToken: next, HasMore: next != ""}
+	for _, c := range page.Comments {
+		resp.Comments = append(resp.Comments, models.Comment{
+			ID:         c.CommentID,
+			Author:     c.Author,
+			AuthorID:   pipedID(c.CommentorURL),
+			Text:       htmlToText(c.CommentText),
+			Published:  c.CommentedTime,
+			LikeCount:  c.LikeCount,
+			ReplyCount: c.ReplyCount,
+			Pinned:     c.Pinned,
+			ByUploader: c.ChannelOwner,
+		})
+	}
+	return resp, nil
+}
+
+// FetchCaptionCues downloads a WebVTT track; Piped hands out absolute URLs of its proxy
+func (p *PipedProvider) FetchCaptionCues(ctx context.Context, track models.CaptionTrack) ([]models.CaptionCue, error) {
+	if track.URL == "" {
+		return nil, errors.New("caption track has no URL")
+	}
+	body, err := p.instances.getRaw(ctx, p.client, track.URL)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCaptions(string(body))
+}
+
+func pipedResults(items []pipedItem) []models.SearchResult {
+	var results []models.SearchResult
+	for _, it := range items {
+		id := pipedID(it.URL)
+		if id == "" {
+			continue
+		}
+		channelID := pipedID(it.UploaderURL)
+
+		switch it.Type {
+		case "stream":
+			result := models.SearchResult{
+				ID:           id,
+				Title:        it.Title,
+				URL:          youtubeWatchBase + id,
+				Thumbnail:    "https://i.ytimg.com/vi/" + id + "/hqdefault.jpg",
+				ChannelName:  it.UploaderName,
+				ChannelID:    channelID,
+				ChannelURl:   channelURL(channelID),
+				IsLive:       it.Duration < 0,
+				IsShort:      it.IsShort,
+				ViewCount:    max(it.Views, 0),
+				Published:    it.UploadedDate,
+				PublishedAge: utils.ParseRelativeTime(it.UploadedDate),
+				Snippet:      it.ShortDescription,
+				Verified:     it.UploaderVerified,
+			}
+			if it.Duration > 0 {
+				result.Duration = utils.FormatDuration(it.Duration)
+				result.DurationSec = it.Duration
+			}
+			results = append(results, result)
+		case "playlist":
+			results = append(results, models.SearchResult{
+				Kind:        models.ResultPlaylist,
+				ID:          id,
+				Title:       it.Name,
+				URL:         youtubePlaylistBase + id,
+				Thumbnail:   it.Thumbnail,
+				ChannelName: it.UploaderName,
+				ChannelID:   channelID,
+				ChannelURl:  channelURL(channelID),
+				VideoCount:  it.Videos,
+				Verified:    it.UploaderVerified,
+			})
+		case "channel":
+			results = append(results, models.SearchResult{
+				Kind:        models.ResultChannel,
+				ID:          id,
+				Title:       it.Name,
+				URL:         channelURL(id),
+				Thumbnail:   it.Thumbnail,
+				ChannelID:   id,
+				ChannelURl:  channelURL(id),
+				Subscribers: max(it.Subscribers, 0),
+				VideoCount:  max(it.Videos, 0),
+				Snippet:     it.Description,
+				Verified:    it.Verified,
+			})
+		}
+	}
+	return results
+}
+
+// pipedID takes the id out of the relative links Piped uses: /watch?v=, /playlist?list= and /channel/
+func pipedID(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case u.Path == "/watch":
+		return u.Query().Get("v")
+	case u.Path == "/playlist":
+		return u.Query().Get("list")
+	case strings.HasPrefix(u.Path, "/channel/"):
+		return strings.TrimPrefix(u.Path, "/channel/")
+	}
+	return ""
+}
+
+// pipedNextPage builds the continuation of a search: the same query on the nextpage endpoint
+func pipedNextPage(ref, endpoint, nextpage string) string {
+	if nextpage == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Set("nextpage", nextpage)
+	return endpoint + "?" + q.Encode()
+}
+
+// htmlToText turns the HTML descriptions and comments of Piped into plain text
+func htmlToText(s string) string {
+	for _, br := range []string{"<br>", "<br/>", "<br />"} {
+		s = strings.ReplaceAll(s, br, "\n")
+	}
+	return cleanCaptionText(s)
+}
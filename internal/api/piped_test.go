@@ -0,0 +1,227 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Drack112/go-youtube/internal/models"
+)
+
+func TestPipedSearch(t *testing.T) {
+	var asked url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asked = r.URL.Query()
+		switch r.URL.Path {
+		case "/search":
+			io.WriteString(w, `{"items":[
+				{"type":"stream","url":"/watch?v=oV9rvDllKEg","title":"Concurrency is not parallelism","uploaderName":"gnbitcom","uploaderUrl":"/channel/UCarZKD-Bf3zD2GRTqvBBqiw","uploadedDate":"11 years ago","duration":1900,"views":912345,"uploaderVerified":true},
+				{"type":"stream","url":"/watch?v=jfKfPfyJRdk","title":"lofi hip hop radio","uploaderUrl":"/channel/UCSJ4gkVC6NrvII8umztf0Ow","duration":-1,"views":-1},
+				{"type":"channel","url":"/channel/UC_BzFbxG2za3bp5NRRRXJSw","name":"The Go Programming Language","thumbnail":"https://yt3.example/go","subscribers":160000,"videos":-1,"verified":true},
+				{"type":"playlist","url":"/playlist?list=PL2ntRZ1ySWBdD9bru6IR-_WXUgJqvrtx9","name":"GopherCon 2023","uploaderName":"Gopher Academy","videos":42}
+			],"nextpage":"{\"page\":2}"}`)
+		case "/nextpage/search":
+			io.WriteString(w, `{"items":[],"nextpage":null}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	p := NewPipedProvider(newRESTClient(srv), []string{srv.URL})
+
+	resp, err := p.SearchWithOptions(t.Context(), fixtureQuery, models.SearchOptions{Type: models.TypeVideo}, "")
+	if err != nil {
+		t.Fatalf("SearchWithOptions: %v", err)
+	}
+	if asked.Get("filter") != "videos" || asked.Get("q") != fixtureQuery {
+		t.Errorf("query = %v", asked)
+	}
+
+	if len(resp.Results) != 4 {
+		t.Fatalf("got %d results, want 4", len(resp.Results))
+	}
+	video, live, channel, playlist := resp.Results[0], resp.Results[1], resp.Results[2], resp.Results[3]
+	if video.ID != "oV9rvDllKEg" || video.ChannelID != "UCarZKD-Bf3zD2GRTqvBBqiw" || video.DurationSec != 1900 || !video.Verified {
+		t.Errorf("video = %+v", video)
+	}
+	if !live.IsLive || live.ViewCount != 0 || live.Duration != "" {
+		t.Errorf("live = %+v", live)
+	}
+	if channel.Kind != models.ResultChannel || channel.ID != "UC_BzFbxG2za3bp5NRRRXJSw" || channel.Subscribers != 160000 || channel.VideoCount != 0 {
+		t.Errorf("channel = %+v", channel)
+	}
+	if playlist.Kind != models.ResultPlaylist || playlist.ID != "PL2ntRZ1ySWBdD9bru6IR-_WXUgJqvrtx9" || playlist.VideoCount != 42 {
+		t.Errorf("playlist = %+v", playlist)
+	}
+
+	if !resp.HasMore {
+		t.Fatal("nextpage should leave more results")
+	}
+	next, err := p.SearchWithOptions(t.Context(), fixtureQuery, models.SearchOptions{}, resp.ContinuationToken)
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if asked.Get("nextpage") != `{"page":2}` || asked.Get("filter") != "videos" {
+		t.Errorf("second page query = %v", asked)
+	}
+	if next.HasMore {
+		t.Error("a page without nextpage is the last one")
+	}
+}
+
+func TestPipedVideo(t *testing.T) {
+	srv := newRESTServer(t, map[string]string{
+		"/streams/" + fixtureVideoID: `{
+			"title":"Go Concurrency Patterns","description":"Rob Pike &amp; friends<br>Slides: <a href=\"https://talks.golang.org\">talks.golang.org</a>",
+			"uploadDate":"2012-07-02T00:00:00.000Z","uploader":"Google for Developers","uploaderUrl":"/channel/UC_x5XG1OV2P6uZZ5FSM9Ttw",
+			"uploaderSubscriberCount":2400000,"duration":3060,"views":1000,"likes":20,"livestream":false,"category":"Education","tags":["go"],
+			"thumbnailUrl":"https://pipedproxy.example/vi/f6kdp27TYZs/maxresdefault.jpg",
+			"videoStreams":[
+				{"url":"https://pipedproxy.example/videoplayback?itag=18","mimeType":"video/mp4","codec":"avc1.42001E","itag":18,"quality":"360p","width":640,"height":360,"fps":30,"videoOnly":false},
+				{"url":"https://pipedproxy.example/videoplayback?itag=137","mimeType":"video/mp4","codec":"avc1.640028","itag":137,"quality":"1080p","width":1920,"height":1080,"fps":30,"videoOnly":true,"contentLength":90000000}
+			],
+			"audioStreams":[{"url":"https://pipedproxy.example/videoplayback?itag=140","mimeType":"audio/mp4","codec":"mp4a.40.2","itag":140,"quality":"128 kbps","bitrate":130000,"videoOnly":false}],
+			"subtitles":[{"url":"https://pipedproxy.example/api/timedtext?v=f6kdp27TYZs&lang=en","mimeType":"text/vtt","name":"English","code":"en","autoGenerated":false}],
+			"chapters":[{"title":"Intro","start":0},{"title":"Generators","start":310,"image":"https://pipedproxy.example/chapter.jpg"}]
+		}`,
+	})
+	p := NewPipedProvider(newRESTClient(srv), []string{srv.URL})
+
+	video, err := p.GetVideo(t.Context(), fixtureVideoID)
+	if err != nil {
+		t.Fatalf("GetVideo: %v", err)
+	}
+	if video.Description != "Rob Pike & friends\nSlides: talks.golang.org" {
+		t.Errorf("description = %q", video.Description)
+	}
+	if video.Channel.ID != "UC_x5XG1OV2P6uZZ5FSM9Ttw" || video.Channel.SubscriberCount != 2_400_000 || video.LikeCount != 20 {
+		t.Errorf("video = %+v", video)
+	}
+	if len(video.Formats) != 1 || video.Formats[0].Itag != 18 {
+		t.Errorf("formats = %+v", video.Formats)
+	}
+	if len(video.AdaptiveFormats) != 2 || !video.AdaptiveFormats[0].IsVideoOnly || video.AdaptiveFormats[0].QualityLabel != "1080p" ||
+		!video.AdaptiveFormats[1].IsAudioOnly || video.AdaptiveFormats[1].QualityLabel != "" {
+		t.Errorf("adaptive formats = %+v", video.AdaptiveFormats)
+	}
+	if len(video.Captions) != 1 || video.Captions[0].LanguageCode != "en" || video.Captions[0].Format != "vtt" {
+		t.Errorf("captions = %+v", video.Captions)
+	}
+	if len(video.Chapters) != 2 || video.Chapters[1].StartSec != 310 || video.Chapters[1].Thumbnail == "" {
+		t.Errorf("chapters = %+v", video.Chapters)
+	}
+}
+
+func TestPipedChannel(t *testing.T) {
+	var asked *url.URL
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asked = r.URL
+		switch r.URL.Path {
+		case "/c/@GoogleDevelopers":
+			io.WriteString(w, `{"id":"UC_x5XG1OV2P6uZZ5FSM9Ttw","name":"Google for Developers","avatarUrl":"https://yt3.example/avatar","subscriberCount":2400000,"verified":true,
+				"relatedStreams":[{"type":"stream","url":"/watch?v=f6kdp27TYZs","title":"Go Concurrency Patterns","duration":3060}],
+				"nextpage":"page2",
+				"tabs":[{"name":"shorts","data":"shorts-data"},{"name":"playlists","data":"playlists-data"}]}`)
+		case "/channels/tabs":
+			io.WriteString(w, `{"content":[{"type":"playlist","url":"/playlist?list=PLtLJO5JKE5YDKG4WcaNts3IVZqhDmmuBH","name":"Go","videos":12}],"nextpage":null}`)
+		case "/nextpage/channel/UC_x5XG1OV2P6uZZ5FSM9Ttw":
+			io.WriteString(w, `{"relatedStreams":[{"type":"stream","url":"/watch?v=oV9rvDllKEg","title":"Older talk","duration":1900}],"nextpage":null}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	p := NewPipedProvider(newRESTClient(srv), []string{srv.URL})
+
+	videos, err := p.GetChannel(t.Context(), fixtureChannel, ChannelTabVideos, "")
+	if err != nil {
+		t.Fatalf("GetChannel: %v", err)
+	}
+	if videos.Channel.Handle != "@GoogleDevelopers" || videos.Channel.SubscriberCount != 2_400_000 {
+		t.Errorf("channel = %+v", videos.Channel)
+	}
+	if len(videos.Results) != 1 || videos.Results[0].ChannelName != "Google for Developers" || !videos.HasMore {
+		t.Fatalf("videos = %+v", videos)
+	}
+
+	older, err := p.GetChannel(t.Context(), fixtureChannel, ChannelTabVideos, videos.ContinuationToken)
+	if err != nil {
+		t.Fatalf("next page: %v", err)
+	}
+	if asked.Query().Get("nextpage") != "page2" || len(older.Results) != 1 || older.HasMore {
+		t.Errorf("next page %v = %+v", asked, older)
+	}
+
+	playlists, err := p.GetChannel(t.Context(), fixtureChannel, ChannelTabPlaylists, "")
+	if err != nil {
+		t.Fatalf("playlists tab: %v", err)
+	}
+	if asked.Query().Get("data") != "playlists-data" || len(playlists.Results) != 1 || playlists.Results[0].Kind != models.ResultPlaylist {
+		t.Errorf("playlists %v = %+v", asked, playlists)
+	}
+
+	live, err := p.GetChannel(t.Context(), fixtureChannel, ChannelTabLive, "")
+	if err != nil {
+		t.Fatalf("live tab: %v", err)
+	}
+	if len(live.Results) != 0 || live.HasMore {
+		t.Errorf("channel without a livestreams tab = %+v", live)
+	}
+}
+
+func TestPipedPlaylist(t *testing.T) {
+	srv := newRESTServer(t, map[string]string{
+		"/playlists/" + fixturePlaylist: `{"name":"Go talks","thumbnailUrl":"https://pipedproxy.example/pl.jpg","description":"","uploader":"Gopher","uploaderUrl":"/channel/UCgopher","videos":2,
+			"relatedStreams":[{"type":"stream","url":"/watch?v=f6kdp27TYZs","title":"Go Concurrency Patterns","duration":3060}],"nextpage":"p2"}`,
+		"/nextpage/playlists/" + fixturePlaylist: `{"relatedStreams":[{"type":"stream","url":"/watch?v=oV9rvDllKEg","title":"Concurrency is not parallelism","duration":1900}],"nextpage":null}`,
+	})
+	p := NewPipedProvider(newRESTClient(srv), []string{srv.URL})
+
+	first, err := p.GetPlaylist(t.Context(), fixturePlaylist, "")
+	if err != nil {
+		t.Fatalf("GetPlaylist: %v", err)
+	}
+	if first.Playlist.Title != "Go talks" || first.Playlist.OwnerID != "UCgopher" || first.Playlist.VideoCount != 2 {
+		t.Errorf("playlist = %+v", first.Playlist)
+	}
+	if len(first.Results) != 1 || !first.HasMore {
+		t.Fatalf("first page = %+v", first)
+	}
+
+	second, err := p.GetPlaylist(t.Context(), fixturePlaylist, first.ContinuationToken)
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if second.Playlist != nil || len(second.Results) != 1 || second.Results[0].ID != "oV9rvDllKEg" || second.HasMore {
+		t.Errorf("second page = %+v", second)
+	}
+}
+
+func TestPipedComments(t *testing.T) {
+	srv := newRESTServer(t, map[string]string{
+		"/comments/" + fixtureVideoID: `{"comments":[
+			{"commentId":"Ugz1","author":"@gopher","commentorUrl":"/channel/UCgopher","commentText":"great talk<br>thanks","commentedTime":"2 years ago","likeCount":31,"replyCount":4,"pinned":false,"channelOwner":true}
+		],"nextpage":"c2","disabled":false}`,
+		"/comments/" + fixtureLiveID: `{"comments":[],"disabled":true}`,
+	})
+	p := NewPipedProvider(newRESTClient(srv), []string{srv.URL})
+
+	resp, err := p.GetComments(t.Context(), fixtureVideoID, "")
+	if err != nil {
+		t.Fatalf("GetComments: %v", err)
+	}
+	want := models.Comment{ID: "Ugz1", Author: "@gopher", AuthorID: "UCgopher", Text: "great talk\nthanks", Published: "2 years ago", LikeCount: 31, ReplyCount: 4, ByUploader: true}
+	if len(resp.Comments) != 1 || resp.Comments[0] != want {
+		t.Errorf("comments = %+v, want %+v", resp.Comments, want)
+	}
+	if !resp.HasMore || resp.ContinuationToken != "/nextpage/comments/"+fixtureVideoID+"?nextpage=c2" {
+		t.Errorf("continuation = %q", resp.ContinuationToken)
+	}
+
+	if _, err := p.GetComments(t.Context(), fixtureLiveID, ""); !errors.Is(err, ErrCommentsDisabled) {
+		t.Errorf("want ErrCommentsDisabled, got %v", err)
+	}
+}
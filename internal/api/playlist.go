@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+const youtubePlaylistBase = "https://www.youtube.com/playlist?list="
+
+type PlaylistResponse struct {
+	Playlist          *models.Playlist // only filled on the first page
+	Results           []models.SearchResult
+	ContinuationToken string
+	HasMore           bool
+}
+
+// GetPlaylist fetches the first page of a playlist, or the next batch of items when continuationToken is set
+func (c *Client) GetPlaylist(ctx context.Context, id string, continuationToken string) (*PlaylistResponse, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("empty playlist id")
+	}
+
+	if continuationToken != "" {
+		return c.playlistContinuation(ctx, continuationToken)
+	}
+
+	if !c.Scrape {
+		resp, err := c.playlistInnertube(ctx, id)
+		if err == nil || !scrapeAfter(ctx, err) {
+			return resp, err
+		}
+		c.log().Warn("[GetPlaylist] innertube browse failed, reading the playlist page", "id", id, "error", err)
+	}
+	return c.playlistHTML(ctx, id)
+}
+
+// playlistInnertube browses VL<id>, the browse id behind the playlist page
+func (c *Client) playlistInnertube(ctx context.Context, id string) (*PlaylistResponse, error) {
+	c.log().Debug("[playlistInnertube] browsing playlist", "id", id)
+
+	body, err := c.postInnertube(ctx, InnertubeWeb, "browse", map[string]any{"browseId": "VL" + id})
+	if err != nil {
+		c.log().Error("[playlistInnertube] request failed", "error", err)
+		return nil, err
+	}
+
+	var page browsePage
+	if err := c.decode(body, &page); err != nil {
+		c.log().Error("[playlistInnertube] failed to decode response", "error", err)
+		return nil, err
+	}
+	return playlistResponse(id, &page)
+}
+
+func (c *Client) playlistHTML(ctx context.Context, id string) (*PlaylistResponse, error) {
+	c.log().Debug("[playlistHTML] fetching playlist page", "id", id)
+
+	body, err := c.fetch(ctx, "/playlist?list="+id)
+	if err != nil {
+		c.log().Error("[playlistHTML] fetch failed", "error", err)
+		return nil, err
+	}
+
+	jsonData, err := extractInitialData(body)
+	if err != nil {
+		c.log().Error("[playlistHTML] failed to extract ytInitialData", "error", err)
+		return nil, pageError(body, err)
+	}
+
+	var page browsePage
+	if err := c.decode(jsonData, &page); err != nil {
+		c.log().Error("[playlistHTML] failed to decode ytInitialData", "error", err)
+		return nil, err
+	}
+	return playlistResponse(id, &page)
+}
+
+func playlistResponse(id string, page *browsePage) (*PlaylistResponse, error) {
+	contents, ok := playlistContents(page)
+	if !ok {
+		return nil, layoutError(errors.New("playlist contents missing"))
+	}
+
+	results := parseItems(contents)
+	continuation := findContinuationToken(contents)
+
+	playlist := parsePlaylistHeader(page)
+	playlist.ID = id
+	playlist.URL = youtubePlaylistBase + id
+	if playlist.VideoCount == 0 {
+		playlist.VideoCount = len(results)
+	}
+
+	return &PlaylistResponse{
+		Playlist:          playlist,
+		Results:           results,
+		ContinuationToken: continuation,
+		HasMore:           continuation != "",
+	}, nil
+}
+
+func (c *Client) playlistContinuation(ctx context.Context, token string) (*PlaylistResponse, error) {
+	c.log().Debug("[playlistContinuation] fetching next page", "token", token)
+
+	body, err := c.postInnertube(ctx, InnertubeWeb, "browse", map[string]any{
+		"continuation": token,
+	})
+	if err != nil {
+		c.log().Error("[playlistContinuation] request failed", "error", err)
+		return nil, err
+	}
+
+	var resp continuationResponse
+	if err := c.decode(body, &resp); err != nil {
+		c.log().Error("[playlistContinuation] failed to decode response", "error", err)
+		return nil, err
+	}
+
+	items, err := continuationItems(&resp)
+	if err != nil {
+		c.log().Error("[playlistContinuation] failed to parse results", "error", err)
+		return nil, err
+	}
+
+	continuation := findContinuationToken(items)
+	return &PlaylistResponse{
+		Results:           parseItems(items),
+		ContinuationToken: continuation,
+		HasMore:           continuation != "",
+	}, nil
+}
+
+// playlistContents returns the items of the playlistVideoListRenderer, which sits in a section of the first tab
+func playlistContents(page *browsePage) ([]contentItem, bool) {
+	for _, tab := range page.Contents.TwoColumnBrowseResultsRenderer.Tabs {
+		if tab.TabRenderer == nil || tab.TabRenderer.Content.SectionListRenderer == nil {
+			continue
+		}
+		for _, section := range tab.TabRenderer.Content.SectionListRenderer.Contents {
+			if section.ItemSectionRenderer == nil {
+				continue
+			}
+			for _, item := range section.ItemSectionRenderer.Contents {
+				if item.PlaylistVideoListRenderer != nil {
+					return item.PlaylistVideoListRenderer.Contents, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// parsePlaylistHeader reads title, owner and counts from whichever header layout the page uses
+func parsePlaylistHeader(page *browsePage) *models.Playlist {
+	playlist := &models.Playlist{}
+
+	if meta := page.Metadata.PlaylistMetadataRenderer; meta != nil {
+		playlist.Title = meta.Title
+		playlist.Description = meta.Description
+	}
+
+	if mf := page.Microformat.MicroformatDataRenderer; mf != nil {
+		playlist.Thumbnail = mf.Thumbnail.largest()
+	}
+
+	// classic header and sidebar layout
+	if header := page.Header.PlaylistHeaderRenderer; header != nil {
+		if playlist.Title == "" {
+			playlist.Title = header.Title.String()
+		}
+		playlist.OwnerName = header.OwnerText.String()
+		playlist.OwnerID = header.OwnerText.browseID()
+		playlist.VideoCount = int(utils.ParseCount(header.NumVideosText.String()))
+		playlist.ViewCount = utils.ParseCount(header.ViewCountText.String())
+	}
+
+	if sidebar := page.Sidebar.PlaylistSidebarRenderer; sidebar != nil {
+		for _, item := range sidebar.Items {
+			if primary := item.PlaylistSidebarPrimaryInfoRenderer; primary != nil {
+				for i := range primary.Stats {
+					parsePlaylistStat(playlist, primary.Stats[i].String())
+				}
+			}
+			if secondary := item.PlaylistSidebarSecondaryInfoRenderer; secondary != nil && playlist.OwnerName == "" {
+				if owner := secondary.VideoOwner.VideoOwnerRenderer; owner != nil {
+					playlist.OwnerName = owner.Title.String()
+					playlist.OwnerID = owner.NavigationEndpoint.BrowseEndpoint.BrowseID
+				}
+			}
+		}
+	}
+
+	// newer pageHeaderRenderer layout keeps everything in metadata rows, the owner is the part linking
+	// to a channel, whatever the language of the byline around it
+	for _, part := range page.Header.metadataParts() {
+		name, id := part.AvatarStack.AvatarStackViewModel.Text.browseLink()
+		if id == "" {
+			name, id = part.Text.browseLink()
+		}
+		if id != "" {
+			if playlist.OwnerName == "" {
+				playlist.OwnerName, playlist.OwnerID = name, id
+			}
+			continue
+		}
+		parsePlaylistStat(playlist, part.Text.Content)
+	}
+
+	if header := page.Header.PageHeaderRenderer; header != nil && playlist.Title == "" {
+		playlist.Title = header.PageTitle
+	}
+
+	return playlist
+}
+
+func parsePlaylistStat(playlist *models.Playlist, text string) {
+	switch {
+	case utils.IsVideoCount(text) && playlist.VideoCount == 0:
+		playlist.VideoCount = int(utils.ParseCount(text))
+	case utils.IsViewCount(text) && playlist.ViewCount == 0:
+		playlist.ViewCount = utils.ParseCount(text)
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Drack112/go-youtube/internal/models"
+)
+
+func TestGetPlaylist(t *testing.T) {
+	c := newTestClient(t)
+
+	resp, err := c.GetPlaylist(t.Context(), fixturePlaylist, "")
+	if err != nil {
+		t.Fatalf("GetPlaylist: %v", err)
+	}
+	if resp.Playlist == nil || resp.Playlist.Title == "" {
+		t.Fatalf("playlist header missing: %+v", resp.Playlist)
+	}
+	for _, r := range resp.Results {
+		if r.ID == "" || r.Title == "" {
+			t.Errorf("incomplete playlist item: %+v", r)
+		}
+	}
+	assertGolden(t, "playlist", resp)
+
+	if !resp.HasMore {
+		t.Fatal("first playlist page should carry a continuation token")
+	}
+	next, err := c.GetPlaylist(t.Context(), fixturePlaylist, resp.ContinuationToken)
+	if err != nil {
+		t.Fatalf("continuation: %v", err)
+	}
+	assertGolden(t, "playlist_continuation", next)
+}
+
+func TestParsePlaylistPageHeader(t *testing.T) {
+	// a pt-BR byline; the owner comes from the command run, "de " is never looked at
+	const data = `{"header":{"pageHeaderRenderer":{"pageTitle":"Concorrência em Go","content":{"pageHeaderViewModel":{
+		"metadata":{"contentMetadataViewModel":{"metadataRows":[
+			{"metadataParts":[{"avatarStack":{"avatarStackViewModel":{"text":{"content":"de Gophers do Brasil 🇧🇷",
+				"commandRuns":[{"startIndex":3,"length":22,"onTap":{"innertubeCommand":{"browseEndpoint":{"browseId":"UCgophers"}}}}]}}}}]},
+			{"metadataParts":[{"text":{"content":"Playlist"}},{"text":{"content":"42 vídeos"}},{"text":{"content":"1,2 mil visualizações"}}]}
+		]}}}}}}}`
+
+	var page browsePage
+	if err := json.Unmarshal([]byte(data), &page); err != nil {
+		t.Fatal(err)
+	}
+	got := parsePlaylistHeader(&page)
+	want := models.Playlist{Title: "Concorrência em Go", OwnerName: "Gophers do Brasil 🇧🇷", OwnerID: "UCgophers", VideoCount: 42, ViewCount: 1200}
+	if *got != want {
+		t.Errorf("playlist = %+v, want %+v", *got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Drack112/go-youtube/internal/models"
+)
+
+// Provider is a source of YouTube content. Client reads YouTube itself, InvidiousProvider and PipedProvider
+// ask a self-hosted frontend for the same data. Continuation tokens only make sense to the provider that
+// handed them out.
+type Provider interface {
+	// Name identifies the backend in logs and the UI
+	Name() string
+	SearchWithOptions(ctx context.Context, input string, opts models.SearchOptions, continuationToken string) (*SearchResponse, error)
+	GetVideo(ctx context.Context, id string) (*models.Video, error)
+	GetChannel(ctx context.Context, ref string, tab ChannelTab, continuationToken string) (*ChannelResponse, error)
+	GetPlaylist(ctx context.Context, id string, continuationToken string) (*PlaylistResponse, error)
+	GetComments(ctx context.Context, videoID string, continuationToken string) (*CommentsResponse, error)
+	FetchCaptionCues(ctx context.Context, track models.CaptionTrack) ([]models.CaptionCue, error)
+}
+
+type CommentsResponse struct {
+	Comments          []models.Comment
+	ContinuationToken string
+	HasMore           bool
+}
+
+// Backends lists the names NewProvider accepts
+var Backends = []string{"youtube", "invidious", "piped"}
+
+var (
+	_ Provider = (*Client)(nil)
+	_ Provider = (*InvidiousProvider)(nil)
+	_ Provider = (*PipedProvider)(nil)
+)
+
+func (c *Client) Name() string {
+	return "youtube"
+}
+
+// NewProvider returns the provider of backend. The REST backends send their requests through c, sharing
+// its HTTP client, headers, timeout and locale, and fail over across instances, base URLs such as
+// https://invidious.example.org.
+func NewProvider(backend string, instances []string, c *Client) (Provider, error) {
+	switch backend {
+	case "", "youtube":
+		return c, nil
+	case "invidious", "piped":
+		if len(instances) == 0 {
+			return nil, fmt.Errorf("backend %s needs at least one instance URL", backend)
+		}
+		if backend == "invidious" {
+			return NewInvidiousProvider(c, instances), nil
+		}
+		return NewPipedProvider(c, instances), nil
+	}
+	return nil, fmt.Errorf("unknown backend %q (use youtube, invidious, piped)", backend)
+}
@@ -0,0 +1,406 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// parseItems converts a content list into results, unwrapping the containers (rich items, shelves,
+// sections, grids) that search, channel and playlist pages nest the renderers in
+func parseItems(items []contentItem) []models.SearchResult {
+	var results []models.SearchResult
+	for i := range items {
+		results = appendItem(results, &items[i])
+	}
+	return results
+}
+
+func appendItem(results []models.SearchResult, item *contentItem) []models.SearchResult {
+	var parsed *models.SearchResult
+
+	switch {
+	case item.VideoRenderer != nil:
+		parsed = parseVideoRenderer(item.VideoRenderer)
+	case item.GridVideoRenderer != nil:
+		parsed = parseVideoRenderer(item.GridVideoRenderer)
+	case item.MovieRenderer != nil:
+		parsed = parseMovieRenderer(item.MovieRenderer)
+	case item.ReelItemRenderer != nil:
+		parsed = parseShortRenderer(item.ReelItemRenderer)
+	case item.ShortsLockupViewModel != nil:
+		parsed = parseShortsLockup(item.ShortsLockupViewModel)
+	case item.ChannelRenderer != nil:
+		parsed = parseChannelRenderer(item.ChannelRenderer)
+	case item.GridChannelRenderer != nil:
+		parsed = parseChannelRenderer(item.GridChannelRenderer)
+	case item.PlaylistRenderer != nil:
+		parsed = parsePlaylistRenderer(item.PlaylistRenderer)
+	case item.GridPlaylistRenderer != nil:
+		parsed = parsePlaylistRenderer(item.GridPlaylistRenderer)
+	case item.RadioRenderer != nil:
+		parsed = parseRadioRenderer(item.RadioRenderer)
+	case item.CompactRadioRenderer != nil:
+		parsed = parseRadioRenderer(item.CompactRadioRenderer)
+	case item.LockupViewModel != nil:
+		parsed = parseLockupViewModel(item.LockupViewModel)
+	case item.PlaylistVideoRenderer != nil:
+		parsed = parsePlaylistVideoRenderer(item.PlaylistVideoRenderer)
+	case item.RichItemRenderer != nil:
+		return appendItem(results, &item.RichItemRenderer.Content)
+	case item.ShelfRenderer != nil:
+		return append(results, parseShelf(item.ShelfRenderer)...)
+	case item.ReelShelfRenderer != nil:
+		return append(results, parseShelf(item.ReelShelfRenderer)...)
+	case item.ItemSectionRenderer != nil:
+		return append(results, parseItems(item.ItemSectionRenderer.Contents)...)
+	case item.GridRenderer != nil:
+		return append(results, parseItems(item.GridRenderer.Items)...)
+	case item.PlaylistVideoListRenderer != nil:
+		return append(results, parseItems(item.PlaylistVideoListRenderer.Contents)...)
+	}
+
+	if parsed != nil {
+		results = append(results, *parsed)
+	}
+	return results
+}
+
+func parseShelf(shelf *shelfRenderer) []models.SearchResult {
+	if shelf.Items != nil {
+		return parseItems(shelf.Items)
+	}
+
+	for _, list := range []*itemList{shelf.Content.VerticalListRenderer, shelf.Content.HorizontalListRenderer, shelf.Content.GridRenderer} {
+		if list != nil {
+			return parseItems(list.Items)
+		}
+	}
+
+	return nil
+}
+
+func parseVideoRenderer(r *videoRenderer) *models.SearchResult {
+	if r.VideoID == "" {
+		return nil
+	}
+
+	channelID := r.OwnerText.browseID()
+	channelURL := ""
+	if channelID != "" {
+		channelURL = youtubeBase + "/channel/" + channelID
+	}
+
+	isLive := false
+	for _, b := range r.Badges {
+		if b.MetadataBadgeRenderer.Style == "BADGE_STYLE_TYPE_LIVE_NOW" {
+			isLive = true
+		}
+	}
+	for _, o := range r.ThumbnailOverlays {
+		// alternative live indicator
+		if o.ThumbnailOverlayTimeStatusRenderer != nil && o.ThumbnailOverlayTimeStatusRenderer.Style == "LIVE" {
+			isLive = true
+		}
+	}
+
+	dur := r.LengthText.String()
+	result := &models.SearchResult{
+		ID:          r.VideoID,
+		Title:       r.Title.String(),
+		URL:         youtubeWatchBase + r.VideoID,
+		Thumbnail:   videoThumbnail(&r.Thumbnail, r.VideoID),
+		ChannelName: r.OwnerText.String(),
+		ChannelID:   channelID,
+		ChannelURl:  channelURL,
+		Duration:    dur,
+		DurationSec: utils.ParseDuration(dur),
+		IsLive:      isLive,
+	}
+	parseResultMetadata(result, r)
+
+	return result
+}
+
+func parseMovieRenderer(r *videoRenderer) *models.SearchResult {
+	result := parseVideoRenderer(r)
+	if result == nil {
+		return nil
+	}
+
+	result.Kind = models.ResultMovie
+	if result.ChannelName == "" {
+		result.ChannelName = r.LongBylineText.String()
+	}
+	if result.Duration == "" && len(r.ThumbnailOverlays) > 0 && r.ThumbnailOverlays[0].ThumbnailOverlayTimeStatusRenderer != nil {
+		// movies keep their runtime in a thumbnail overlay instead of lengthText
+		result.Duration = r.ThumbnailOverlays[0].ThumbnailOverlayTimeStatusRenderer.Text.String()
+		result.DurationSec = utils.ParseDuration(result.Duration)
+	}
+
+	return result
+}
+
+// parseResultMetadata fills the view count, age, snippet and badges that video-like renderers carry
+func parseResultMetadata(result *models.SearchResult, r *videoRenderer) {
+	views := r.ViewCountText.String()
+	if views == "" {
+		views = r.ShortViewCountText.String()
+	}
+	result.ViewCount = utils.ParseCount(views)
+
+	result.Published = r.PublishedTimeText.String()
+	result.PublishedAge = utils.ParseRelativeTime(result.Published)
+
+	if len(r.DetailedMetadataSnippets) > 0 {
+		result.Snippet = r.DetailedMetadataSnippets[0].SnippetText.String()
+	}
+	if result.Snippet == "" {
+		result.Snippet = r.DescriptionSnippet.String()
+	}
+
+	result.Verified, result.Artist = ownerFlags(r.OwnerBadges)
+	result.Badges = resultBadges(r.Badges)
+}
+
+func parseShortRenderer(r *reelItemRenderer) *models.SearchResult {
+	if r.VideoID == "" {
+		return nil
+	}
+
+	channelID := r.ShortBylineText.browseID()
+	channelURL := ""
+	if channelID != "" {
+		channelURL = youtubeBase + "/channel/" + channelID
+	}
+
+	return &models.SearchResult{
+		ID:          r.VideoID,
+		Title:       r.Headline.String(),
+		URL:         youtubeBase + "/shorts/" + r.VideoID,
+		Thumbnail:   videoThumbnail(&r.Thumbnail, r.VideoID),
+		ChannelName: r.ShortBylineText.String(),
+		ChannelID:   channelID,
+		ChannelURl:  channelURL,
+		Duration:    "SHORT",
+		IsShort:     true,
+		ViewCount:   utils.ParseCount(r.ViewCountText.String()),
+	}
+}
+
+func parseShortsLockup(r *shortsLockupViewModel) *models.SearchResult {
+	id := r.OnTap.InnertubeCommand.ReelWatchEndpoint.VideoID
+	if id == "" {
+		id = strings.TrimPrefix(r.EntityID, "shorts-shelf-item-")
+	}
+	if id == "" {
+		return nil
+	}
+
+	return &models.SearchResult{
+		ID:        id,
+		Title:     r.OverlayMetadata.PrimaryText.Content,
+		URL:       youtubeBase + "/shorts/" + id,
+		Thumbnail: "https://i.ytimg.com/vi/" + id + "/hqdefault.jpg",
+		Duration:  "SHORT",
+		IsShort:   true,
+		ViewCount: utils.ParseCount(r.OverlayMetadata.SecondaryText.Content),
+	}
+}
+
+func parsePlaylistRenderer(r *playlistRenderer) *models.SearchResult {
+	if r.PlaylistID == "" {
+		return nil
+	}
+
+	return &models.SearchResult{
+		Kind:        models.ResultPlaylist,
+		ID:          r.PlaylistID,
+		Title:       r.Title.String(),
+		URL:         youtubePlaylistBase + r.PlaylistID,
+		Thumbnail:   r.thumbnail(),
+		ChannelName: r.ShortBylineText.String(),
+		VideoCount:  r.videoCount(),
+	}
+}
+
+// parseRadioRenderer handles mixes, auto-generated playlists that only play through the watch page
+func parseRadioRenderer(r *playlistRenderer) *models.SearchResult {
+	videoID := r.NavigationEndpoint.WatchEndpoint.VideoID
+	if r.PlaylistID == "" || videoID == "" {
+		return nil
+	}
+
+	return &models.SearchResult{
+		Kind:        models.ResultMix,
+		ID:          r.PlaylistID,
+		Title:       r.Title.String(),
+		URL:         mixURL(videoID, r.PlaylistID),
+		Thumbnail:   r.thumbnail(),
+		ChannelName: r.LongBylineText.String(),
+		VideoCount:  r.videoCount(),
+	}
+}
+
+func (r *playlistRenderer) thumbnail() string {
+	if thumb := r.Thumbnail.largest(); thumb != "" {
+		return thumb
+	}
+	// playlistRenderer keeps one thumbnail set per preview video
+	if len(r.Thumbnails) > 0 {
+		return r.Thumbnails[0].largest()
+	}
+	return ""
+}
+
+func (r *playlistRenderer) videoCount() int {
+	count := r.VideoCountText.String()
+	if count == "" {
+		count = r.VideoCountShortText.String()
+	}
+	if count == "" {
+		count = r.VideoCount
+	}
+	return int(utils.ParseCount(count))
+}
+
+// parseLockupViewModel handles the newer lockup layout used for playlists and mixes
+func parseLockupViewModel(r *lockupViewModel) *models.SearchResult {
+	if r.ContentID == "" || r.ContentType != "LOCKUP_CONTENT_TYPE_PLAYLIST" {
+		return nil
+	}
+
+	result := &models.SearchResult{
+		Kind:  models.ResultPlaylist,
+		ID:    r.ContentID,
+		Title: r.Metadata.LockupMetadataViewModel.Title.Content,
+		URL:   youtubePlaylistBase + r.ContentID,
+	}
+
+	// mix ids start with RD and can only be opened through the first video of the mix
+	if videoID := r.RendererContext.CommandContext.OnTap.InnertubeCommand.WatchEndpoint.VideoID; strings.HasPrefix(r.ContentID, "RD") && videoID != "" {
+		result.Kind = models.ResultMix
+		result.URL = mixURL(videoID, r.ContentID)
+	}
+
+	if thumb := r.thumbnail(); thumb != nil {
+		result.Thumbnail = lastThumbnailURL(thumb.Image.Sources)
+	badges:
+		for _, o := range thumb.Overlays {
+			for _, b := range o.ThumbnailOverlayBadgeViewModel.ThumbnailBadges {
+				if n := utils.ParseCount(b.ThumbnailBadgeViewModel.Text); n > 0 {
+					result.VideoCount = int(n)
+					break badges
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func parseChannelRenderer(r *channelRenderer) *models.SearchResult {
+	if r.ChannelID == "" {
+		return nil
+	}
+
+	url := youtubeBase + "/channel/" + r.ChannelID
+	if path := r.NavigationEndpoint.BrowseEndpoint.CanonicalBaseURL; path != "" {
+		url = youtubeBase + path
+	}
+
+	// newer layouts show the @handle in subscriberCountText and move the subscriber count to videoCountText
+	var subscribers int64
+	handle := ""
+	for _, t := range []*text{&r.SubscriberCountText, &r.VideoCountText} {
+		s := t.String()
+		switch {
+		case strings.HasPrefix(s, "@"):
+			handle = s
+		case utils.IsSubscriberCount(s) && subscribers == 0:
+			subscribers = utils.ParseCount(s)
+		}
+	}
+
+	verified, artist := ownerFlags(r.OwnerBadges)
+
+	return &models.SearchResult{
+		Kind:        models.ResultChannel,
+		ID:          r.ChannelID,
+		Title:       r.Title.String(),
+		URL:         url,
+		Thumbnail:   r.Thumbnail.largest(),
+		ChannelName: handle,
+		ChannelID:   r.ChannelID,
+		ChannelURl:  url,
+		Subscribers: subscribers,
+		Snippet:     r.DescriptionSnippet.String(),
+		Verified:    verified,
+		Artist:      artist,
+	}
+}
+
+func parsePlaylistVideoRenderer(r *playlistVideoRenderer) *models.SearchResult {
+	if r.VideoID == "" {
+		return nil
+	}
+
+	// deleted and private entries stay in the list but cannot be played
+	if r.IsPlayable != nil && !*r.IsPlayable {
+		return nil
+	}
+
+	dur := r.LengthText.String()
+	durSec, err := strconv.Atoi(r.LengthSeconds)
+	if err != nil {
+		durSec = utils.ParseDuration(dur)
+	}
+
+	channelID := r.ShortBylineText.browseID()
+	channelURL := ""
+	if channelID != "" {
+		channelURL = youtubeBase + "/channel/" + channelID
+	}
+
+	isLive := len(r.ThumbnailOverlays) > 0 && r.ThumbnailOverlays[0].ThumbnailOverlayTimeStatusRenderer != nil &&
+		r.ThumbnailOverlays[0].ThumbnailOverlayTimeStatusRenderer.Style == "LIVE"
+
+	result := &models.SearchResult{
+		ID:          r.VideoID,
+		Title:       r.Title.String(),
+		URL:         youtubeWatchBase + r.VideoID,
+		Thumbnail:   videoThumbnail(&r.Thumbnail, r.VideoID),
+		ChannelName: r.ShortBylineText.String(),
+		ChannelID:   channelID,
+		ChannelURl:  channelURL,
+		Duration:    dur,
+		DurationSec: durSec,
+		IsLive:      isLive,
+	}
+
+	// videoInfo reads "1.2M views • 3 years ago"
+	for _, run := range r.VideoInfo.Runs {
+		if utils.IsViewCount(run.Text) {
+			result.ViewCount = utils.ParseCount(run.Text)
+		} else if age := utils.ParseRelativeTime(run.Text); age > 0 {
+			result.Published = run.Text
+			result.PublishedAge = age
+		}
+	}
+
+	return result
+}
+
+// videoThumbnail falls back to the predictable i.ytimg.com URL when the renderer has no thumbnails
+func videoThumbnail(set *thumbnailSet, videoID string) string {
+	if thumb := set.largest(); thumb != "" {
+		return thumb
+	}
+	return "https://i.ytimg.com/vi/" + videoID + "/hqdefault.jpg"
+}
+
+func mixURL(videoID, listID string) string {
+	return youtubeWatchBase + videoID + "&list=" + listID
+}
@@ -0,0 +1,894 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/Drack112/go-youtube/internal/models"
+)
+
+// Typed views of the ytInitialData, ytInitialPlayerResponse and youtubei/v1 layouts the parsers read.
+// encoding/json only fills the fields declared here and skips every other subtree, so a page decodes
+// without building a map[string]any for each of its nodes.
+
+// searchPage is the ytInitialData of /results
+type searchPage struct {
+	Contents struct {
+		TwoColumnSearchResultsRenderer struct {
+			PrimaryContents struct {
+				SectionListRenderer struct {
+					Contents []contentItem `json:"contents"`
+				} `json:"sectionListRenderer"`
+			} `json:"primaryContents"`
+		} `json:"twoColumnSearchResultsRenderer"`
+	} `json:"contents"`
+}
+
+// browsePage is the ytInitialData of channel and playlist pages
+type browsePage struct {
+	Contents struct {
+		TwoColumnBrowseResultsRenderer struct {
+			Tabs []struct {
+				TabRenderer *tabRenderer `json:"tabRenderer"`
+			} `json:"tabs"`
+		} `json:"twoColumnBrowseResultsRenderer"`
+	} `json:"contents"`
+	Header   browseHeader `json:"header"`
+	Metadata struct {
+		ChannelMetadataRenderer  *channelMetadataRenderer `json:"channelMetadataRenderer"`
+		PlaylistMetadataRenderer *struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+		} `json:"playlistMetadataRenderer"`
+	} `json:"metadata"`
+	Microformat struct {
+		MicroformatDataRenderer *struct {
+			Thumbnail thumbnailSet `json:"thumbnail"`
+		} `json:"microformatDataRenderer"`
+	} `json:"microformat"`
+	Sidebar struct {
+		PlaylistSidebarRenderer *struct {
+			Items []struct {
+				PlaylistSidebarPrimaryInfoRenderer *struct {
+					Stats []text `json:"stats"`
+				} `json:"playlistSidebarPrimaryInfoRenderer"`
+				PlaylistSidebarSecondaryInfoRenderer *struct {
+					VideoOwner struct {
+						VideoOwnerRenderer *videoOwnerRenderer `json:"videoOwnerRenderer"`
+					} `json:"videoOwner"`
+				} `json:"playlistSidebarSecondaryInfoRenderer"`
+			} `json:"items"`
+		} `json:"playlistSidebarRenderer"`
+	} `json:"sidebar"`
+}
+
+// selectedTab returns the tab the page was opened on, or nil
+func (p *browsePage) selectedTab() *tabRenderer {
+	for _, t := range p.Contents.TwoColumnBrowseResultsRenderer.Tabs {
+		if t.TabRenderer != nil && t.TabRenderer.Selected {
+			return t.TabRenderer
+		}
+	}
+	return nil
+}
+
+type tabRenderer struct {
+	Selected bool `json:"selected"`
+	Endpoint struct {
+		CommandMetadata struct {
+			WebCommandMetadata struct {
+				URL string `json:"url"`
+			} `json:"webCommandMetadata"`
+		} `json:"commandMetadata"`
+	} `json:"endpoint"`
+	Content struct {
+		RichGridRenderer *struct {
+			Contents []contentItem `json:"contents"`
+		} `json:"richGridRenderer"`
+		SectionListRenderer *struct {
+			Contents []contentItem `json:"contents"`
+		} `json:"sectionListRenderer"`
+	} `json:"content"`
+}
+
+// browseHeader holds whichever header layout a channel or playlist page uses
+type browseHeader struct {
+	C4TabbedHeaderRenderer *struct {
+		SubscriberCountText text            `json:"subscriberCountText"`
+		ChannelHandleText   text            `json:"channelHandleText"`
+		Badges              []metadataBadge `json:"badges"`
+	} `json:"c4TabbedHeaderRenderer"`
+	PlaylistHeaderRenderer *struct {
+		Title         text `json:"title"`
+		OwnerText     text `json:"ownerText"`
+		NumVideosText text `json:"numVideosText"`
+		ViewCountText text `json:"viewCountText"`
+	} `json:"playlistHeaderRenderer"`
+	PageHeaderRenderer *struct {
+		PageTitle string `json:"pageTitle"`
+		Content   struct {
+			PageHeaderViewModel struct {
+				Title struct {
+					DynamicTextViewModel struct {
+						Text viewModelText `json:"text"`
+					} `json:"dynamicTextViewModel"`
+				} `json:"title"`
+				Metadata struct {
+					ContentMetadataViewModel struct {
+						MetadataRows []struct {
+							MetadataParts []metadataPart `json:"metadataParts"`
+						} `json:"metadataRows"`
+					} `json:"contentMetadataViewModel"`
+				} `json:"metadata"`
+			} `json:"pageHeaderViewModel"`
+		} `json:"content"`
+	} `json:"pageHeaderRenderer"`
+}
+
+// metadataParts returns the parts of every metadata row of a pageHeaderRenderer, in order
+func (h *browseHeader) metadataParts() []metadataPart {
+	if h.PageHeaderRenderer == nil {
+		return nil
+	}
+	var parts []metadataPart
+	for _, row := range h.PageHeaderRenderer.Content.PageHeaderViewModel.Metadata.ContentMetadataViewModel.MetadataRows {
+		parts = append(parts, row.MetadataParts...)
+	}
+	return parts
+}
+
+// metadataPart is one entry of a metadata row: plain text, or the avatars and byline of an owner
+type metadataPart struct {
+	Text        viewModelText `json:"text"`
+	AvatarStack struct {
+		AvatarStackViewModel struct {
+			Text viewModelText `json:"text"`
+		} `json:"avatarStackViewModel"`
+	} `json:"avatarStack"`
+}
+
+type channelMetadataRenderer struct {
+	ExternalID       string       `json:"externalId"`
+	Title            string       `json:"title"`
+	Description      string       `json:"description"`
+	VanityChannelURL string       `json:"vanityChannelUrl"`
+	Avatar           thumbnailSet `json:"avatar"`
+}
+
+// playerResponse is the answer of youtubei/v1/player, ytInitialPlayerResponse on the watch page
+type playerResponse struct {
+	PlayabilityStatus playabilityStatus `json:"playabilityStatus"`
+	VideoDetails      *videoDetails     `json:"videoDetails"`
+	Microformat       struct {
+		PlayerMicroformatRenderer *playerMicroformat `json:"playerMicroformatRenderer"`
+	} `json:"microformat"`
+	StreamingData *struct {
+		ExpiresInSeconds string         `json:"expiresInSeconds"`
+		Formats          []streamFormat `json:"formats"`
+		AdaptiveFormats  []streamFormat `json:"adaptiveFormats"`
+	} `json:"streamingData"`
+	Captions struct {
+		PlayerCaptionsTracklistRenderer struct {
+			CaptionTracks []captionTrack `json:"captionTracks"`
+		} `json:"playerCaptionsTracklistRenderer"`
+	} `json:"captions"`
+	Storyboards struct {
+		PlayerStoryboardSpecRenderer struct {
+			Spec string `json:"spec"`
+		} `json:"playerStoryboardSpecRenderer"`
+	} `json:"storyboards"`
+}
+
+type playabilityStatus struct {
+	Status      string `json:"status"`
+	Reason      string `json:"reason"`
+	ErrorScreen struct {
+		PlayerErrorMessageRenderer struct {
+			Reason    text `json:"reason"`
+			Subreason text `json:"subreason"`
+		} `json:"playerErrorMessageRenderer"`
+	} `json:"errorScreen"`
+	DesktopLegacyAgeGateReason *int `json:"desktopLegacyAgeGateReason"`
+}
+
+type videoDetails struct {
+	VideoID          string       `json:"videoId"`
+	Title            string       `json:"title"`
+	ShortDescription string       `json:"shortDescription"`
+	LengthSeconds    number       `json:"lengthSeconds"`
+	ViewCount        number       `json:"viewCount"`
+	AverageRating    float32      `json:"averageRating"`
+	IsLive           bool         `json:"isLive"`
+	IsLiveContent    bool         `json:"isLiveContent"`
+	IsUpcoming       bool         `json:"isUpcoming"`
+	Keywords         []string     `json:"keywords"`
+	Thumbnail        thumbnailSet `json:"thumbnail"`
+	ChannelID        string       `json:"channelId"`
+	Author           string       `json:"author"`
+}
+
+type playerMicroformat struct {
+	Category        string `json:"category"`
+	UploadDate      string `json:"uploadDate"`
+	PublishDate     string `json:"publishDate"`
+	OwnerProfileURL string `json:"ownerProfileUrl"`
+	Description     text   `json:"description"`
+}
+
+type streamFormat struct {
+	Itag             number `json:"itag"`
+	URL              string `json:"url"`
+	MimeType         string `json:"mimeType"`
+	QualityLabel     string `json:"qualityLabel"`
+	Bitrate          number `json:"bitrate"`
+	AverageBitrate   number `json:"averageBitrate"`
+	Width            number `json:"width"`
+	Height           number `json:"height"`
+	FPS              number `json:"fps"`
+	ContentLength    number `json:"contentLength"`
+	ApproxDurationMs number `json:"approxDurationMs"`
+	AudioQuality     string `json:"audioQuality"`
+	AudioChannels    number `json:"audioChannels"`
+	ColorInfo        struct {
+		TransferCharacteristics string `json:"transferCharacteristics"`
+	} `json:"colorInfo"`
+}
+
+type captionTrack struct {
+	BaseURL      string `json:"baseUrl"`
+	Name         text   `json:"name"`
+	LanguageCode string `json:"languageCode"`
+	Kind         string `json:"kind"`
+}
+
+// watchNext is the answer of youtubei/v1/next, ytInitialData on the watch page
+type watchNext struct {
+	Contents struct {
+		TwoColumnWatchNextResults struct {
+			Results struct {
+				Results struct {
+					Contents []watchItem `json:"contents"`
+				} `json:"results"`
+			} `json:"results"`
+		} `json:"twoColumnWatchNextResults"`
+	} `json:"contents"`
+	PlayerOverlays struct {
+		PlayerOverlayRenderer struct {
+			DecoratedPlayerBarRenderer struct {
+				DecoratedPlayerBarRenderer struct {
+					PlayerBar struct {
+						MultiMarkersPlayerBarRenderer struct {
+							MarkersMap []struct {
+								Value struct {
+									Chapters []struct {
+										ChapterRenderer *chapterRenderer `json:"chapterRenderer"`
+									} `json:"chapters"`
+								} `json:"value"`
+							} `json:"markersMap"`
+						} `json:"multiMarkersPlayerBarRenderer"`
+					} `json:"playerBar"`
+				} `json:"decoratedPlayerBarRenderer"`
+			} `json:"decoratedPlayerBarRenderer"`
+		} `json:"playerOverlayRenderer"`
+	} `json:"playerOverlays"`
+	EngagementPanels []struct {
+		EngagementPanelSectionListRenderer struct {
+			Content struct {
+				MacroMarkersListRenderer *struct {
+					Contents []struct {
+						MacroMarkersListItemRenderer *macroMarkersListItemRenderer `json:"macroMarkersListItemRenderer"`
+					} `json:"contents"`
+				} `json:"macroMarkersListRenderer"`
+				StructuredDescriptionContentRenderer *struct {
+					Items []struct {
+						VideoDescriptionMusicSectionRenderer *struct {
+							CarouselLockups []struct {
+								CarouselLockupRenderer struct {
+									InfoRows []struct {
+										InfoRowRenderer *infoRowRenderer `json:"infoRowRenderer"`
+									} `json:"infoRows"`
+								} `json:"carouselLockupRenderer"`
+							} `json:"carouselLockups"`
+						} `json:"videoDescriptionMusicSectionRenderer"`
+					} `json:"items"`
+				} `json:"structuredDescriptionContentRenderer"`
+			} `json:"content"`
+		} `json:"engagementPanelSectionListRenderer"`
+	} `json:"engagementPanels"`
+	FrameworkUpdates struct {
+		EntityBatchUpdate struct {
+			Mutations []struct {
+				Payload struct {
+					LikeCountEntity *struct {
+						LikeCountIfIndifferentNumber number `json:"likeCountIfIndifferentNumber"`
+					} `json:"likeCountEntity"`
+				} `json:"payload"`
+			} `json:"mutations"`
+		} `json:"entityBatchUpdate"`
+	} `json:"frameworkUpdates"`
+}
+
+// watchItem is one entry of the main column of the watch page
+type watchItem struct {
+	VideoPrimaryInfoRenderer *struct {
+		ViewCount struct {
+			VideoViewCountRenderer struct {
+				ViewCount text `json:"viewCount"`
+			} `json:"videoViewCountRenderer"`
+		} `json:"viewCount"`
+		VideoActions struct {
+			MenuRenderer struct {
+				TopLevelButtons []struct {
+					SegmentedLikeDislikeButtonViewModel struct {
+						LikeButtonViewModel struct {
+							LikeButtonViewModel struct {
+								ToggleButtonViewModel struct {
+									ToggleButtonViewModel struct {
+										DefaultButtonViewModel struct {
+											ButtonViewModel struct {
+												AccessibilityText string `json:"accessibilityText"`
+											} `json:"buttonViewModel"`
+										} `json:"defaultButtonViewModel"`
+									} `json:"toggleButtonViewModel"`
+								} `json:"toggleButtonViewModel"`
+							} `json:"likeButtonViewModel"`
+						} `json:"likeButtonViewModel"`
+					} `json:"segmentedLikeDislikeButtonViewModel"`
+				} `json:"topLevelButtons"`
+			} `json:"menuRenderer"`
+		} `json:"videoActions"`
+	} `json:"videoPrimaryInfoRenderer"`
+	VideoSecondaryInfoRenderer *struct {
+		Owner struct {
+			VideoOwnerRenderer *videoOwnerRenderer `json:"videoOwnerRenderer"`
+		} `json:"owner"`
+	} `json:"videoSecondaryInfoRenderer"`
+	ItemSectionRenderer *struct {
+		SectionIdentifier string `json:"sectionIdentifier"`
+		Contents          []struct {
+			CommentsEntryPointHeaderRenderer *struct {
+				CommentCount text `json:"commentCount"`
+			} `json:"commentsEntryPointHeaderRenderer"`
+			ContinuationItemRenderer *continuationItemRenderer `json:"continuationItemRenderer"`
+		} `json:"contents"`
+	} `json:"itemSectionRenderer"`
+}
+
+type videoOwnerRenderer struct {
+	Title               text               `json:"title"`
+	Thumbnail           thumbnailSet       `json:"thumbnail"`
+	NavigationEndpoint  navigationEndpoint `json:"navigationEndpoint"`
+	SubscriberCountText text               `json:"subscriberCountText"`
+	Badges              []metadataBadge    `json:"badges"`
+}
+
+type chapterRenderer struct {
+	Title                text         `json:"title"`
+	TimeRangeStartMillis *int         `json:"timeRangeStartMillis"`
+	Thumbnail            thumbnailSet `json:"thumbnail"`
+}
+
+type macroMarkersListItemRenderer struct {
+	Title           text         `json:"title"`
+	TimeDescription text         `json:"timeDescription"`
+	Thumbnail       thumbnailSet `json:"thumbnail"`
+	OnTap           struct {
+		WatchEndpoint struct {
+			StartTimeSeconds *int `json:"startTimeSeconds"`
+		} `json:"watchEndpoint"`
+	} `json:"onTap"`
+}
+
+// infoRowRenderer is a row of the music section of a description, such as SONG or ARTIST
+type infoRowRenderer struct {
+	Title            text `json:"title"`
+	DefaultMetadata  text `json:"defaultMetadata"`
+	ExpandedMetadata text `json:"expandedMetadata"`
+}
+
+// commentsPage is the answer of the next continuation that loads a comment section
+type commentsPage struct {
+	OnResponseReceivedEndpoints []struct {
+		ReloadContinuationItemsCommand struct {
+			ContinuationItems []commentItem `json:"continuationItems"`
+		} `json:"reloadContinuationItemsCommand"`
+		AppendContinuationItemsAction struct {
+			ContinuationItems []commentItem `json:"continuationItems"`
+		} `json:"appendContinuationItemsAction"`
+	} `json:"onResponseReceivedEndpoints"`
+	FrameworkUpdates struct {
+		EntityBatchUpdate struct {
+			Mutations []struct {
+				EntityKey string `json:"entityKey"`
+				Payload   struct {
+					CommentEntityPayload *commentEntityPayload `json:"commentEntityPayload"`
+				} `json:"payload"`
+			} `json:"mutations"`
+		} `json:"entityBatchUpdate"`
+	} `json:"frameworkUpdates"`
+}
+
+type commentItem struct {
+	CommentThreadRenderer *struct {
+		CommentViewModel struct {
+			CommentViewModel *commentViewModel `json:"commentViewModel"`
+		} `json:"commentViewModel"`
+		Comment struct {
+			CommentRenderer *commentRenderer `json:"commentRenderer"`
+		} `json:"comment"`
+	} `json:"commentThreadRenderer"`
+	ContinuationItemRenderer *continuationItemRenderer `json:"continuationItemRenderer"`
+}
+
+type commentViewModel struct {
+	CommentKey string `json:"commentKey"`
+	PinnedText string `json:"pinnedText"`
+}
+
+type commentEntityPayload struct {
+	Properties struct {
+		CommentID string `json:"commentId"`
+		Content   struct {
+			Content string `json:"content"`
+		} `json:"content"`
+		PublishedTime string `json:"publishedTime"`
+	} `json:"properties"`
+	Author struct {
+		DisplayName string `json:"displayName"`
+		ChannelID   string `json:"channelId"`
+		IsCreator   bool   `json:"isCreator"`
+	} `json:"author"`
+	Toolbar struct {
+		LikeCountNotliked string `json:"likeCountNotliked"`
+		ReplyCount        string `json:"replyCount"`
+	} `json:"toolbar"`
+}
+
+type commentRenderer struct {
+	CommentID            string             `json:"commentId"`
+	AuthorText           text               `json:"authorText"`
+	AuthorEndpoint       navigationEndpoint `json:"authorEndpoint"`
+	ContentText          text               `json:"contentText"`
+	PublishedTimeText    text               `json:"publishedTimeText"`
+	VoteCount            text               `json:"voteCount"`
+	ReplyCount           number             `json:"replyCount"`
+	PinnedCommentBadge   *struct{}          `json:"pinnedCommentBadge"`
+	AuthorIsChannelOwner bool               `json:"authorIsChannelOwner"`
+}
+
+// continuationResponse is the answer of youtubei/v1 search and browse continuations
+type continuationResponse struct {
+	OnResponseReceivedCommands []continuationAction `json:"onResponseReceivedCommands"`
+	OnResponseReceivedActions  []continuationAction `json:"onResponseReceivedActions"`
+}
+
+type continuationAction struct {
+	AppendContinuationItemsAction struct {
+		ContinuationItems []contentItem `json:"continuationItems"`
+	} `json:"appendContinuationItemsAction"`
+	ReloadContinuationItemsCommand struct {
+		ContinuationItems []contentItem `json:"continuationItems"`
+	} `json:"reloadContinuationItemsCommand"`
+}
+
+// contentItem is one entry of a content list. Exactly one of its renderers is normally set.
+type contentItem struct {
+	VideoRenderer         *videoRenderer         `json:"videoRenderer"`
+	GridVideoRenderer     *videoRenderer         `json:"gridVideoRenderer"`
+	MovieRenderer         *videoRenderer         `json:"movieRenderer"`
+	ReelItemRenderer      *reelItemRenderer      `json:"reelItemRenderer"`
+	ShortsLockupViewModel *shortsLockupViewModel `json:"shortsLockupViewModel"`
+	ChannelRenderer       *channelRenderer       `json:"channelRenderer"`
+	GridChannelRenderer   *channelRenderer       `json:"gridChannelRenderer"`
+	PlaylistRenderer      *playlistRenderer      `json:"playlistRenderer"`
+	GridPlaylistRenderer  *playlistRenderer      `json:"gridPlaylistRenderer"`
+	RadioRenderer         *playlistRenderer      `json:"radioRenderer"`
+	CompactRadioRenderer  *playlistRenderer      `json:"compactRadioRenderer"`
+	LockupViewModel       *lockupViewModel       `json:"lockupViewModel"`
+	PlaylistVideoRenderer *playlistVideoRenderer `json:"playlistVideoRenderer"`
+
+	// containers
+	RichItemRenderer *struct {
+		Content contentItem `json:"content"`
+	} `json:"richItemRenderer"`
+	ShelfRenderer       *shelfRenderer `json:"shelfRenderer"`
+	ReelShelfRenderer   *shelfRenderer `json:"reelShelfRenderer"`
+	ItemSectionRenderer *struct {
+		Contents []contentItem `json:"contents"`
+	} `json:"itemSectionRenderer"`
+	GridRenderer *struct {
+		Items []contentItem `json:"items"`
+	} `json:"gridRenderer"`
+	PlaylistVideoListRenderer *struct {
+		Contents []contentItem `json:"contents"`
+	} `json:"playlistVideoListRenderer"`
+
+	ContinuationItemRenderer *continuationItemRenderer `json:"continuationItemRenderer"`
+}
+
+type shelfRenderer struct {
+	// reel shelves list their items directly, regular shelves wrap them in a list renderer
+	Items   []contentItem `json:"items"`
+	Content struct {
+		VerticalListRenderer   *itemList `json:"verticalListRenderer"`
+		HorizontalListRenderer *itemList `json:"horizontalListRenderer"`
+		GridRenderer           *itemList `json:"gridRenderer"`
+	} `json:"content"`
+}
+
+type itemList struct {
+	Items []contentItem `json:"items"`
+}
+
+// videoRenderer covers videoRenderer, gridVideoRenderer and movieRenderer, which share their fields
+type videoRenderer struct {
+	VideoID                  string             `json:"videoId"`
+	Title                    text               `json:"title"`
+	Thumbnail                thumbnailSet       `json:"thumbnail"`
+	OwnerText                text               `json:"ownerText"`
+	ShortBylineText          text               `json:"shortBylineText"`
+	LongBylineText           text               `json:"longBylineText"`
+	LengthText               text               `json:"lengthText"`
+	ViewCountText            text               `json:"viewCountText"`
+	ShortViewCountText       text               `json:"shortViewCountText"`
+	PublishedTimeText        text               `json:"publishedTimeText"`
+	DescriptionSnippet       text               `json:"descriptionSnippet"`
+	DetailedMetadataSnippets []metadataSnippet  `json:"detailedMetadataSnippets"`
+	Badges                   []metadataBadge    `json:"badges"`
+	OwnerBadges              []metadataBadge    `json:"ownerBadges"`
+	ThumbnailOverlays        []thumbnailOverlay `json:"thumbnailOverlays"`
+}
+
+type metadataSnippet struct {
+	SnippetText text `json:"snippetText"`
+}
+
+type reelItemRenderer struct {
+	VideoID         string       `json:"videoId"`
+	Headline        text         `json:"headline"`
+	Thumbnail       thumbnailSet `json:"thumbnail"`
+	ShortBylineText text         `json:"shortBylineText"`
+	ViewCountText   text         `json:"viewCountText"`
+}
+
+type shortsLockupViewModel struct {
+	EntityID string `json:"entityId"`
+	OnTap    struct {
+		InnertubeCommand navigationEndpoint `json:"innertubeCommand"`
+	} `json:"onTap"`
+	OverlayMetadata struct {
+		PrimaryText   viewModelText `json:"primaryText"`
+		SecondaryText viewModelText `json:"secondaryText"`
+	} `json:"overlayMetadata"`
+}
+
+type channelRenderer struct {
+	ChannelID           string             `json:"channelId"`
+	Title               text               `json:"title"`
+	Thumbnail           thumbnailSet       `json:"thumbnail"`
+	NavigationEndpoint  navigationEndpoint `json:"navigationEndpoint"`
+	SubscriberCountText text               `json:"subscriberCountText"`
+	VideoCountText      text               `json:"videoCountText"`
+	DescriptionSnippet  text               `json:"descriptionSnippet"`
+	OwnerBadges         []metadataBadge    `json:"ownerBadges"`
+}
+
+// playlistRenderer covers the playlist and radio (mix) renderers
+type playlistRenderer struct {
+	PlaylistID          string             `json:"playlistId"`
+	Title               text               `json:"title"`
+	Thumbnail           thumbnailSet       `json:"thumbnail"`
+	Thumbnails          []thumbnailSet     `json:"thumbnails"` // one set per preview video
+	ShortBylineText     text               `json:"shortBylineText"`
+	LongBylineText      text               `json:"longBylineText"`
+	VideoCount          string             `json:"videoCount"`
+	VideoCountText      text               `json:"videoCountText"`
+	VideoCountShortText text               `json:"videoCountShortText"`
+	NavigationEndpoint  navigationEndpoint `json:"navigationEndpoint"`
+}
+
+// lockupViewModel is the newer layout used for playlists and mixes
+type lockupViewModel struct {
+	ContentID    string `json:"contentId"`
+	ContentType  string `json:"contentType"`
+	ContentImage struct {
+		ThumbnailViewModel           *thumbnailViewModel `json:"thumbnailViewModel"`
+		CollectionThumbnailViewModel struct {
+			PrimaryThumbnail struct {
+				ThumbnailViewModel *thumbnailViewModel `json:"thumbnailViewModel"`
+			} `json:"primaryThumbnail"`
+		} `json:"collectionThumbnailViewModel"`
+	} `json:"contentImage"`
+	Metadata struct {
+		LockupMetadataViewModel struct {
+			Title viewModelText `json:"title"`
+		} `json:"lockupMetadataViewModel"`
+	} `json:"metadata"`
+	RendererContext struct {
+		CommandContext struct {
+			OnTap struct {
+				InnertubeCommand navigationEndpoint `json:"innertubeCommand"`
+			} `json:"onTap"`
+		} `json:"commandContext"`
+	} `json:"rendererContext"`
+}
+
+func (l *lockupViewModel) thumbnail() *thumbnailViewModel {
+	if t := l.ContentImage.CollectionThumbnailViewModel.PrimaryThumbnail.ThumbnailViewModel; t != nil {
+		return t
+	}
+	return l.ContentImage.ThumbnailViewModel
+}
+
+type thumbnailViewModel struct {
+	Image struct {
+		Sources []thumbnail `json:"sources"`
+	} `json:"image"`
+	Overlays []struct {
+		ThumbnailOverlayBadgeViewModel struct {
+			ThumbnailBadges []struct {
+				ThumbnailBadgeViewModel struct {
+					Text string `json:"text"`
+				} `json:"thumbnailBadgeViewModel"`
+			} `json:"thumbnailBadges"`
+		} `json:"thumbnailOverlayBadgeViewModel"`
+	} `json:"overlays"`
+}
+
+type playlistVideoRenderer struct {
+	VideoID           string             `json:"videoId"`
+	IsPlayable        *bool              `json:"isPlayable"`
+	Title             text               `json:"title"`
+	Thumbnail         thumbnailSet       `json:"thumbnail"`
+	ShortBylineText   text               `json:"shortBylineText"`
+	LengthText        text               `json:"lengthText"`
+	LengthSeconds     string             `json:"lengthSeconds"`
+	VideoInfo         text               `json:"videoInfo"`
+	ThumbnailOverlays []thumbnailOverlay `json:"thumbnailOverlays"`
+}
+
+type continuationItemRenderer struct {
+	ContinuationEndpoint continuationEndpoint `json:"continuationEndpoint"`
+	Button               struct {
+		ButtonRenderer struct {
+			Command continuationEndpoint `json:"command"`
+		} `json:"buttonRenderer"`
+	} `json:"button"`
+}
+
+// token returns the continuation of the renderer, which newer layouts put behind a button
+func (r *continuationItemRenderer) token() string {
+	if token := r.ContinuationEndpoint.token(); token != "" {
+		return token
+	}
+	return r.Button.ButtonRenderer.Command.token()
+}
+
+// continuationEndpoint holds the command either directly or wrapped in a commandExecutorCommand
+type continuationEndpoint struct {
+	ContinuationCommand struct {
+		Token string `json:"token"`
+	} `json:"continuationCommand"`
+	CommandExecutorCommand struct {
+		Commands []continuationEndpoint `json:"commands"`
+	} `json:"commandExecutorCommand"`
+}
+
+func (e *continuationEndpoint) token() string {
+	if e.ContinuationCommand.Token != "" {
+		return e.ContinuationCommand.Token
+	}
+	for i := range e.CommandExecutorCommand.Commands {
+		if token := e.CommandExecutorCommand.Commands[i].token(); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+type navigationEndpoint struct {
+	BrowseEndpoint struct {
+		BrowseID         string `json:"browseId"`
+		CanonicalBaseURL string `json:"canonicalBaseUrl"`
+	} `json:"browseEndpoint"`
+	WatchEndpoint struct {
+		VideoID string `json:"videoId"`
+	} `json:"watchEndpoint"`
+	ReelWatchEndpoint struct {
+		VideoID string `json:"videoId"`
+	} `json:"reelWatchEndpoint"`
+}
+
+// text is YouTube's formatted string, sent either as simpleText or as a list of runs
+type text struct {
+	SimpleText string `json:"simpleText"`
+	Runs       []struct {
+		Text               string             `json:"text"`
+		NavigationEndpoint navigationEndpoint `json:"navigationEndpoint"`
+	} `json:"runs"`
+}
+
+func (t *text) String() string {
+	if t.SimpleText != "" || len(t.Runs) == 0 {
+		return t.SimpleText
+	}
+	if len(t.Runs) == 1 {
+		return t.Runs[0].Text
+	}
+
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+// browseID returns the channel the first run links to
+func (t *text) browseID() string {
+	if len(t.Runs) == 0 {
+		return ""
+	}
+	return t.Runs[0].NavigationEndpoint.BrowseEndpoint.BrowseID
+}
+
+// viewModelText is the {"content": ...} text of the view model layouts. Icons inside it, such as the
+// verified check next to a channel name, come as attachment runs.
+type viewModelText struct {
+	Content     string `json:"content"`
+	CommandRuns []struct {
+		StartIndex int `json:"startIndex"`
+		Length     int `json:"length"`
+		OnTap      struct {
+			InnertubeCommand navigationEndpoint `json:"innertubeCommand"`
+		} `json:"onTap"`
+	} `json:"commandRuns"`
+	AttachmentRuns []struct {
+		Element struct {
+			Type struct {
+				ImageType struct {
+					Image struct {
+						Sources []struct {
+							ClientResource struct {
+								ImageName string `json:"imageName"`
+							} `json:"clientResource"`
+						} `json:"sources"`
+					} `json:"image"`
+				} `json:"imageType"`
+			} `json:"type"`
+		} `json:"element"`
+	} `json:"attachmentRuns"`
+}
+
+// browseLink returns the text and target of the first run that opens a channel. Run offsets count UTF-16
+// code units, like in JavaScript.
+func (t *viewModelText) browseLink() (name, browseID string) {
+	for _, run := range t.CommandRuns {
+		browseID = run.OnTap.InnertubeCommand.BrowseEndpoint.BrowseID
+		if browseID == "" {
+			continue
+		}
+		units := utf16.Encode([]rune(t.Content))
+		if run.StartIndex < 0 || run.Length <= 0 || run.StartIndex+run.Length > len(units) {
+			return t.Content, browseID
+		}
+		return string(utf16.Decode(units[run.StartIndex : run.StartIndex+run.Length])), browseID
+	}
+	return "", ""
+}
+
+// imageNames lists the icons attached to the text
+func (t *viewModelText) imageNames() []string {
+	var names []string
+	for _, run := range t.AttachmentRuns {
+		for _, source := range run.Element.Type.ImageType.Image.Sources {
+			names = append(names, source.ClientResource.ImageName)
+		}
+	}
+	return names
+}
+
+// number is an integer YouTube sends either as a JSON number or as a string, like itag and contentLength.
+// Anything else decodes to 0.
+type number int64
+
+func (n *number) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(string(data), `"`)
+	if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		*n = number(v)
+	} else if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		*n = number(f)
+	}
+	return nil
+}
+
+type thumbnailSet struct {
+	Thumbnails []thumbnail `json:"thumbnails"`
+}
+
+type thumbnail struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// largest returns the URL of the last (biggest) thumbnail, made absolute
+func (s *thumbnailSet) largest() string {
+	return lastThumbnailURL(s.Thumbnails)
+}
+
+// list converts the set, smallest first as YouTube sends it
+func (s *thumbnailSet) list() []models.Thumbnail {
+	var thumbs []models.Thumbnail
+	for _, t := range s.Thumbnails {
+		if t.URL == "" {
+			continue
+		}
+		url := t.URL
+		if strings.HasPrefix(url, "//") {
+			url = "https:" + url
+		}
+		thumbs = append(thumbs, models.Thumbnail{URL: url, Width: t.Width, Height: t.Height})
+	}
+	return thumbs
+}
+
+func lastThumbnailURL(thumbs []thumbnail) string {
+	if len(thumbs) == 0 {
+		return ""
+	}
+
+	url := thumbs[len(thumbs)-1].URL
+	switch {
+	case strings.HasPrefix(url, "//"):
+		return "https:" + url
+	case strings.HasPrefix(url, "/"):
+		return youtubeBase + url
+	}
+	return url
+}
+
+type thumbnailOverlay struct {
+	ThumbnailOverlayTimeStatusRenderer *struct {
+		Style string `json:"style"`
+		Text  text   `json:"text"`
+	} `json:"thumbnailOverlayTimeStatusRenderer"`
+}
+
+type metadataBadge struct {
+	MetadataBadgeRenderer struct {
+		Style string `json:"style"`
+		Label string `json:"label"`
+	} `json:"metadataBadgeRenderer"`
+}
+
+// ownerFlags reports whether the owner badges mark a verified channel or an official artist
+func ownerFlags(badges []metadataBadge) (verified bool, artist bool) {
+	for _, b := range badges {
+		switch b.MetadataBadgeRenderer.Style {
+		case "BADGE_STYLE_TYPE_VERIFIED":
+			verified = true
+		case "BADGE_STYLE_TYPE_VERIFIED_ARTIST":
+			verified = true
+			artist = true
+		}
+	}
+	return verified, artist
+}
+
+func resultBadges(badges []metadataBadge) models.ResultBadges {
+	var parsed models.ResultBadges
+	for _, b := range badges {
+		switch strings.ToUpper(b.MetadataBadgeRenderer.Label) {
+		case "4K", "8K":
+			parsed.FourK = true
+		case "HDR":
+			parsed.HDR = true
+		case "CC":
+			parsed.Captions = true
+		case "NEW":
+			parsed.New = true
+		}
+	}
+	return parsed
+}
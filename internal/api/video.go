@@ -0,0 +1,402 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+const youtubeWatchBase = "https://www.youtube.com/watch?v="
+
+// GetVideo requests the player response and watch-next data of id and builds a fully populated models.Video
+func (c *Client) GetVideo(ctx context.Context, id string) (*models.Video, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("empty video id")
+	}
+
+	if !c.Scrape {
+		video, err := c.videoInnertube(ctx, id)
+		if err == nil || !scrapeAfter(ctx, err) {
+			return video, err
+		}
+		c.log().Warn("[GetVideo] innertube player failed, reading the watch page", "id", id, "error", err)
+	}
+	return c.videoHTML(ctx, id)
+}
+
+// videoInnertube asks youtubei/v1/player as PlayerClient, and youtubei/v1/next for what ytInitialData
+// holds on the watch page, both at once
+func (c *Client) videoInnertube(ctx context.Context, id string) (*models.Video, error) {
+	c.log().Debug("[videoInnertube] requesting player", "id", id, "client", c.playerClient().Name)
+
+	next := make(chan *watchNext, 1)
+	go func() {
+		// like ytInitialData the answer is optional
+		data, err := c.watchNext(ctx, id)
+		if err != nil {
+			c.log().Warn("[videoInnertube] next request failed", "id", id, "error", err)
+		}
+		next <- data
+	}()
+
+	body, err := c.postInnertube(ctx, c.playerClient(), "player", map[string]any{
+		"videoId":        id,
+		"contentCheckOk": true,
+		"racyCheckOk":    true,
+	})
+	if err != nil {
+		c.log().Error("[videoInnertube] request failed", "error", err)
+		return nil, err
+	}
+
+	return c.buildVideo(id, body, <-next)
+}
+
+// watchNext returns the youtubei/v1/next answer for id: what ytInitialData holds on the watch page
+func (c *Client) watchNext(ctx context.Context, id string) (*watchNext, error) {
+	body, err := c.postInnertube(ctx, InnertubeWeb, "next", map[string]any{"videoId": id})
+	if err != nil {
+		return nil, err
+	}
+
+	var next watchNext
+	if err := c.decode(body, &next); err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
+
+// videoHTML reads ytInitialPlayerResponse and ytInitialData out of the watch page
+func (c *Client) videoHTML(ctx context.Context, id string) (*models.Video, error) {
+	c.log().Debug("[videoHTML] fetching watch page", "id", id)
+
+	body, err := c.fetch(ctx, "/watch?v="+id)
+	if err != nil {
+		c.log().Error("[videoHTML] fetch failed", "error", err)
+		return nil, err
+	}
+
+	// both objects come out of a single pass over the page
+	vars := extractJSONVars(body, "ytInitialPlayerResponse", "ytInitialData")
+
+	playerData := vars["ytInitialPlayerResponse"]
+	if playerData == nil {
+		c.log().Error("[videoHTML] ytInitialPlayerResponse not found")
+		return nil, pageError(body, errors.New("ytInitialPlayerResponse not found"))
+	}
+
+	// ytInitialData is optional: it only adds likes, comments and channel details
+	var next *watchNext
+	if initialData := vars["ytInitialData"]; initialData != nil {
+		next = &watchNext{}
+		if err := c.decode(initialData, next); err != nil {
+			c.log().Warn("[videoHTML] failed to decode ytInitialData", "error", err)
+			next = nil
+		}
+	} else {
+		c.log().Warn("[videoHTML] ytInitialData not found")
+	}
+
+	return c.buildVideo(id, playerData, next)
+}
+
+// buildVideo decodes the player response and checks that the video is playable before parsing it
+func (c *Client) buildVideo(id string, playerData []byte, next *watchNext) (*models.Video, error) {
+	var player playerResponse
+	if err := c.decode(playerData, &player); err != nil {
+		c.log().Error("[GetVideo] failed to decode player response", "error", err)
+		return nil, err
+	}
+
+	if err := playabilityError(&player.PlayabilityStatus); err != nil {
+		c.log().Warn("[GetVideo] video is not playable", "id", id, "error", err)
+		return nil, err
+	}
+
+	video := parseVideo(&player, next)
+	if video.ID == "" {
+		return nil, layoutError(errors.New("videoDetails missing"))
+	}
+
+	// callers reading fields the parser does not know get the raw response
+	if err := json.Unmarshal(playerData, &video.PlayerResponse); err != nil {
+		return nil, layoutError(err)
+	}
+	return video, nil
+}
+
+func parseVideo(player *playerResponse, next *watchNext) *models.Video {
+	video := &models.Video{}
+
+	if vd := player.VideoDetails; vd != nil {
+		parseVideoDetails(video, vd)
+	}
+	if mf := player.Microformat.PlayerMicroformatRenderer; mf != nil {
+		parseMicroformat(video, mf)
+	}
+
+	parseStreamingData(video, player)
+	video.Captions = parseCaptionTracks(player)
+
+	if spec := player.Storyboards.PlayerStoryboardSpecRenderer.Spec; spec != "" {
+		video.Storyboards = parseStoryboardSpec(spec)
+	}
+
+	if next != nil {
+		parseWatchNextData(video, next)
+	}
+
+	video.Chapters = parseChapters(next, video.Description)
+
+	return video
+}
+
+func parseVideoDetails(video *models.Video, vd *videoDetails) {
+	video.ID = vd.VideoID
+	video.Title = vd.Title
+	video.URL = youtubeWatchBase + video.ID
+	video.Description = vd.ShortDescription
+	video.DurationSeconds = int(vd.LengthSeconds)
+	video.ViewCount = int64(vd.ViewCount)
+	video.Rating = vd.AverageRating
+
+	video.IsLive = vd.IsLive
+	if !video.IsLive {
+		// isLiveContent is also true for finished streams, so only trust it without a duration
+		video.IsLive = vd.IsLiveContent && video.DurationSeconds == 0
+	}
+	video.IsUpcoming = vd.IsUpcoming
+
+	for _, k := range vd.Keywords {
+		if k != "" {
+			video.Keywords = append(video.Keywords, k)
+		}
+	}
+
+	video.Thumbnails = vd.Thumbnail.list()
+
+	video.Channel.ID = vd.ChannelID
+	video.Channel.Name = vd.Author
+	if video.Channel.ID != "" {
+		video.Channel.URL = "https://www.youtube.com/channel/" + video.Channel.ID
+	}
+}
+
+func parseMicroformat(video *models.Video, mf *playerMicroformat) {
+	video.Category = mf.Category
+	video.UploadedAt = mf.UploadDate
+	video.PublishedAt = mf.PublishDate
+
+	if video.Channel.URL == "" {
+		video.Channel.URL = mf.OwnerProfileURL
+	}
+	if video.Description == "" {
+		video.Description = mf.Description.String()
+	}
+}
+
+// parseStoryboardSpec decodes "baseURL|w#h#count#cols#rows#intervalMs#name#sigh|..." into storyboard levels
+func parseStoryboardSpec(spec string) []models.Storyboard {
+	parts := strings.Split(spec, "|")
+	if len(parts) < 2 {
+		return nil
+	}
+
+	base := parts[0]
+	var boards []models.Storyboard
+
+	for level, p := range parts[1:] {
+		fields := strings.Split(p, "#")
+		if len(fields) < 8 {
+			continue
+		}
+
+		width, _ := strconv.Atoi(fields[0])
+		height, _ := strconv.Atoi(fields[1])
+		count, _ := strconv.Atoi(fields[2])
+		cols, _ := strconv.Atoi(fields[3])
+		rows, _ := strconv.Atoi(fields[4])
+		intervalMs, _ := strconv.Atoi(fields[5])
+		name := fields[6]
+		sigh := fields[7]
+
+		url := strings.ReplaceAll(base, "$L", strconv.Itoa(level))
+		url = strings.ReplaceAll(url, "$N", name)
+		if sigh != "" {
+			url += "&sigh=" + sigh
+		}
+
+		boards = append(boards, models.Storyboard{
+			URLTemplate: url,
+			Width:       width,
+			Height:      height,
+			Count:       count,
+			Rows:        rows,
+			Columns:     cols,
+			FrameSec:    intervalMs / 1000,
+		})
+	}
+
+	return boards
+}
+
+func parseWatchNextData(video *models.Video, next *watchNext) {
+	for _, item := range next.Contents.TwoColumnWatchNextResults.Results.Results.Contents {
+		if primary := item.VideoPrimaryInfoRenderer; primary != nil && video.ViewCount == 0 {
+			video.ViewCount = utils.ParseCount(primary.ViewCount.VideoViewCountRenderer.ViewCount.String())
+		}
+		if secondary := item.VideoSecondaryInfoRenderer; secondary != nil && secondary.Owner.VideoOwnerRenderer != nil {
+			parseVideoOwner(&video.Channel, secondary.Owner.VideoOwnerRenderer)
+		}
+		if section := item.ItemSectionRenderer; section != nil {
+			for _, entry := range section.Contents {
+				if header := entry.CommentsEntryPointHeaderRenderer; header != nil {
+					video.CommentCount = utils.ParseCount(header.CommentCount.String())
+				}
+			}
+		}
+	}
+
+	video.LikeCount = parseLikeCount(next)
+	video.MusicMetadata = parseMusicMetadata(next)
+}
+
+func parseLikeCount(next *watchNext) int64 {
+	// Newer layouts keep the like count in the entity store
+	for _, m := range next.FrameworkUpdates.EntityBatchUpdate.Mutations {
+		if entity := m.Payload.LikeCountEntity; entity != nil {
+			return int64(entity.LikeCountIfIndifferentNumber)
+		}
+	}
+
+	// Older layouts only expose it through the like button accessibility label
+	for _, item := range next.Contents.TwoColumnWatchNextResults.Results.Results.Contents {
+		if item.VideoPrimaryInfoRenderer == nil {
+			continue
+		}
+		for _, button := range item.VideoPrimaryInfoRenderer.VideoActions.MenuRenderer.TopLevelButtons {
+			like := button.SegmentedLikeDislikeButtonViewModel.LikeButtonViewModel.LikeButtonViewModel
+			label := like.ToggleButtonViewModel.ToggleButtonViewModel.DefaultButtonViewModel.ButtonViewModel.AccessibilityText
+			if n := firstNumber(label); n > 0 {
+				return n
+			}
+		}
+	}
+
+	return 0
+}
+
+var numberRegex = regexp.MustCompile(`\d[\d,.]*`)
+
+func firstNumber(text string) int64 {
+	match := numberRegex.FindString(text)
+	if match == "" {
+		return 0
+	}
+	match = strings.NewReplacer(",", "", ".", "").Replace(match)
+	n, _ := strconv.ParseInt(match, 10, 64)
+	return n
+}
+
+func parseVideoOwner(channel *models.ChannelInfo, owner *videoOwnerRenderer) {
+	if channel.Name == "" {
+		channel.Name = owner.Title.String()
+	}
+
+	browse := owner.NavigationEndpoint.BrowseEndpoint
+	if browse.BrowseID != "" && channel.ID == "" {
+		channel.ID = browse.BrowseID
+	}
+	if base := browse.CanonicalBaseURL; base != "" {
+		channel.URL = "https://www.youtube.com" + base
+		if strings.HasPrefix(base, "/@") && channel.Handle == "" {
+			channel.Handle = base[1:]
+		}
+	}
+
+	if thumbnail := owner.Thumbnail.largest(); thumbnail != "" {
+		channel.Thumbnail = thumbnail
+	}
+
+	channel.SubscriberCount = utils.ParseCount(owner.SubscriberCountText.String())
+	channel.Verified, channel.OfficialArtist = ownerFlags(owner.Badges)
+}
+
+// parseMusicMetadata reads the song, artist and album rows of the music section of the description
+func parseMusicMetadata(next *watchNext) *models.MusicMetadata {
+	music := &models.MusicMetadata{}
+	found := false
+
+	for _, panel := range next.EngagementPanels {
+		description := panel.EngagementPanelSectionListRenderer.Content.StructuredDescriptionContentRenderer
+		if description == nil {
+			continue
+		}
+		for _, item := range description.Items {
+			if item.VideoDescriptionMusicSectionRenderer == nil {
+				continue
+			}
+			for _, lockup := range item.VideoDescriptionMusicSectionRenderer.CarouselLockups {
+				for _, row := range lockup.CarouselLockupRenderer.InfoRows {
+					if row.InfoRowRenderer != nil && parseInfoRow(music, row.InfoRowRenderer) {
+						found = true
+					}
+				}
+			}
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return music
+}
+
+// parseInfoRow stores a known row in music, reporting whether it was one
+func parseInfoRow(music *models.MusicMetadata, row *infoRowRenderer) bool {
+	value := row.DefaultMetadata.String()
+	if value == "" {
+		value = row.ExpandedMetadata.String()
+	}
+	if value == "" {
+		return false
+	}
+
+	switch strings.ToUpper(row.Title.String()) {
+	case "SONG":
+		music.Song = value
+	case "ARTIST":
+		music.Artist = value
+		if len(row.DefaultMetadata.Runs) > 0 {
+			if base := row.DefaultMetadata.Runs[0].NavigationEndpoint.BrowseEndpoint.CanonicalBaseURL; base != "" {
+				music.ArtistURL = "https://www.youtube.com" + base
+			}
+		}
+	case "ALBUM":
+		music.Album = value
+	case "WRITERS":
+		music.Writers = splitNames(value)
+	case "PRODUCERS":
+		music.Producers = splitNames(value)
+	default:
+		return false
+	}
+	return true
+}
+
+func splitNames(value string) []string {
+	var names []string
+	for _, n := range strings.Split(value, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Drack112/go-youtube/internal/models"
+)
+
+func TestGetVideo(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		live bool
+	}{
+		{name: "video", id: fixtureVideoID},
+		{name: "video_live", id: fixtureLiveID, live: true},
+	}
+
+	c := newTestClient(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video, err := c.GetVideo(t.Context(), tt.id)
+			if err != nil {
+				t.Fatalf("GetVideo(%s): %v", tt.id, err)
+			}
+			if video.ID != tt.id || video.Title == "" {
+				t.Errorf("wrong video details: id=%q title=%q", video.ID, video.Title)
+			}
+			if video.IsLive != tt.live {
+				t.Errorf("IsLive = %v, want %v", video.IsLive, tt.live)
+			}
+
+			// the raw player response is kept for debugging only
+			video.PlayerResponse = nil
+			assertGolden(t, tt.name, video)
+		})
+	}
+}
+
+func TestFetchCaptionCues(t *testing.T) {
+	c := newTestClient(t)
+
+	video, err := c.GetVideo(t.Context(), fixtureVideoID)
+	if err != nil {
+		t.Fatalf("GetVideo: %v", err)
+	}
+
+	track, ok := FindCaptionTrack(video.Captions, "en")
+	if !ok {
+		t.Fatalf("no English track in %+v", video.Captions)
+	}
+	if track.AutoGenerated {
+		t.Error("manual captions should win over auto-generated ones")
+	}
+
+	cues, err := c.FetchCaptionCues(t.Context(), track)
+	if err != nil {
+		t.Fatalf("FetchCaptionCues: %v", err)
+	}
+	if len(cues) == 0 {
+		t.Fatal("no cues decoded")
+	}
+	assertGolden(t, "captions", cues)
+}
+
+func TestFetchCaptionCuesSignedURL(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		io.WriteString(w, `{"events":[{"tStartMs":0,"dDurationMs":1000,"segs":[{"utf8":"hello"}]}]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newRESTClient(srv)
+	c.SetLocale("pt", "BR")
+
+	signed := "v=f6kdp27TYZs&sparams=ip%2Cexpire&signature=AB12&lang=en"
+	if _, err := c.FetchCaptionCues(t.Context(), models.CaptionTrack{URL: srv.URL + "/api/timedtext?" + signed}); err != nil {
+		t.Fatalf("FetchCaptionCues: %v", err)
+	}
+	if want := signed + "&fmt=json3"; query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+}
@@ -0,0 +1,67 @@
+package flags
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigPath returns $XDG_CONFIG_HOME/go-youtube/config, or the platform equivalent
+func ConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-youtube", "config"), nil
+}
+
+// loadConfig sets the flags of set from the config file before the command line is parsed, so flags given
+// on the command line win. Each line is "name = value", named like the flag without the dash, and lines
+// starting with # are comments:
+//
+//	backend = invidious
+//	instances = https://inv.example.org, https://yt.example.net
+//
+// Names the command does not know are skipped, the file is shared by every command. A missing file is fine.
+func loadConfig(set *flag.FlagSet) error {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return applyConfig(set, f.Name(), bufio.NewScanner(f))
+}
+
+func applyConfig(set *flag.FlagSet, path string, scanner *bufio.Scanner) error {
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimPrefix(strings.TrimSpace(name), "-")
+		if !ok || name == "" {
+			return fmt.Errorf("%s:%d: want name = value, got %q", path, n, line)
+		}
+		if set.Lookup(name) == nil {
+			continue
+		}
+		if err := set.Set(name, strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("%s:%d: %s: %w", path, n, name, err)
+		}
+	}
+	return scanner.Err()
+}
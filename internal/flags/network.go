@@ -0,0 +1,161 @@
+package flags
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Drack112/go-youtube/internal/api"
+	"github.com/Drack112/go-youtube/pkg/httpx"
+	"github.com/Drack112/go-youtube/pkg/logger"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// NetworkOptions controls how requests reach YouTube, every command accepts them
+type NetworkOptions struct {
+	Timeout   time.Duration
+	RateLimit float64
+	Retries   int
+	NoCache   bool
+	Offline   bool
+
+	Proxy     string
+	UserAgent string
+	Headers   http.Header
+	Cookies   string // Netscape cookies.txt
+
+	Client string // innertube client of player requests, or "html" to read the pages only
+
+	Backend   string   // youtube, invidious or piped
+	Instances []string // base URLs of the invidious or piped instances, tried in order
+}
+
+// registerNetworkFlags adds the flags behind NetworkOptions to fs
+func (n *NetworkOptions) registerNetworkFlags(fs *flag.FlagSet) {
+	fs.DurationVar(&n.Timeout, "timeout", api.DefaultTimeout, "limit for each request to YouTube, retries included; 0 disables it")
+	fs.Float64Var(&n.RateLimit, "rate", httpx.DefaultOptions.RequestsPerSecond, "requests per second sent to YouTube, 0 removes the limit")
+	fs.IntVar(&n.Retries, "retries", httpx.DefaultOptions.MaxRetries, "retries of a request that failed with a network error, 429 or 5xx")
+	fs.BoolVar(&n.NoCache, "no-cache", false, "do not read or write the response cache")
+	fs.BoolVar(&n.Offline, "offline", false, "answer only from the response cache, without touching the network")
+
+	fs.StringVar(&n.Proxy, "proxy", "", "http, https or socks5 proxy URL, defaults to $HTTPS_PROXY")
+	fs.StringVar(&n.UserAgent, "user-agent", utils.DefaultUserAgent, "User-Agent sent to YouTube, mpv and yt-dlp")
+	n.Headers = http.Header{}
+	fs.Var(headerFlag(n.Headers), "header", `extra "Name: value" header for every request, repeatable`)
+	fs.StringVar(&n.Cookies, "cookies", "", "Netscape cookies.txt to send with requests, also handed to mpv and yt-dlp")
+
+	fs.StringVar(&n.Client, "client", "web", "innertube client video details are requested as (web, mweb, android, tv), or html to scrape the YouTube pages instead")
+
+	fs.StringVar(&n.Backend, "backend", "youtube", "where content comes from (youtube, invidious, piped)")
+	fs.Var((*listFlag)(&n.Instances), "instances", "comma separated base URLs of the invidious or piped instances, the next one is tried when one fails")
+}
+
+// ProxyURL returns -proxy, or $HTTPS_PROXY when the flag is not set
+func (n NetworkOptions) ProxyURL() string {
+	if n.Proxy != "" {
+		return n.Proxy
+	}
+	for _, env := range []string{"HTTPS_PROXY", "https_proxy"} {
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// RequestHeaders returns the User-Agent and the -header values
+func (n NetworkOptions) RequestHeaders() http.Header {
+	headers := n.Headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	if n.UserAgent != "" {
+		headers.Set("User-Agent", n.UserAgent)
+	}
+	return headers
+}
+
+// HTTPOptions applies the flags to the default transport settings, loading the cookie file if one was given
+func (n NetworkOptions) HTTPOptions() (httpx.Options, error) {
+	opts := httpx.DefaultOptions
+	opts.RequestsPerSecond = n.RateLimit
+	opts.MaxRetries = max(n.Retries, 0)
+
+	if raw := n.ProxyURL(); raw != "" {
+		proxy, err := httpx.ParseProxy(raw)
+		if err != nil {
+			return opts, fmt.Errorf("invalid proxy: %w", err)
+		}
+		opts.Proxy = proxy
+	}
+
+	if n.Cookies != "" {
+		jar, err := httpx.LoadCookieFile(n.Cookies)
+		if err != nil {
+			return opts, fmt.Errorf("loading cookies: %w", err)
+		}
+		opts.Jar = jar
+	}
+
+	if n.NoCache && !n.Offline {
+		return opts, nil
+	}
+	dir, err := httpx.DefaultCacheDir()
+	if err != nil {
+		logger.Warn("[Flags] response cache disabled", "error", err)
+		return opts, nil
+	}
+	opts.Cache = &httpx.CacheOptions{
+		Dir:     dir,
+		MaxSize: httpx.DefaultCacheSize,
+		TTL:     httpx.DefaultTTL,
+		Offline: n.Offline,
+	}
+	return opts, nil
+}
+
+// listFlag reads a comma separated list
+type listFlag []string
+
+func (l *listFlag) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = nil
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// headerFlag collects repeated -header "Name: value" flags
+type headerFlag http.Header
+
+func (h headerFlag) String() string {
+	var pairs []string
+	for name, values := range h {
+		for _, v := range values {
+			pairs = append(pairs, name+": "+v)
+		}
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func (h headerFlag) Set(value string) error {
+	name, v, ok := strings.Cut(value, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return fmt.Errorf(`want "Name: value", got %q`, value)
+	}
+	http.Header(h).Add(name, strings.TrimSpace(v))
+	return nil
+}
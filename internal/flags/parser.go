@@ -4,8 +4,10 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/Drack112/go-youtube/internal/models"
 	"github.com/Drack112/go-youtube/pkg/logger"
 	"github.com/Drack112/go-youtube/pkg/utils"
 	"github.com/charmbracelet/huh"
@@ -23,16 +25,35 @@ const (
 	InputNone InputSrc = iota
 	InputYoutubeURL
 	InputSearchQuery
+	InputPlaylistURL
+	InputChannelURL
 )
 
 type Options struct {
 	Debug      bool
 	Quality    string
 	WindowMode string
+	Language   string // hl
+	Region     string // gl
+	NetworkOptions
 
 	Input           string
 	InputKind       InputSrc
 	QualityProvided bool
+
+	Search models.SearchOptions
+	Enrich bool // fetch the details of the results on screen in the background
+}
+
+type CaptionsOptions struct {
+	Input    string
+	Lang     string
+	Format   string
+	Output   string
+	List     bool
+	Language string // hl, the caption language as a locale
+	Region   string // gl
+	NetworkOptions
 }
 
 func ErrorHandler(err error) string {
@@ -69,18 +90,33 @@ func ParseFlags() (*Options, error) {
 	debug := flag.Bool("debug", false, "enable debug mode")
 	help := flag.Bool("help", false, "show help message")
 	versionFlag := flag.Bool("version", false, "show version information")
-	quality := flag.String("quality", "best", "video quality (best, 1080p, 720p, 480p, 360p, audio)")
+	quality := flag.String("quality", "best", "video quality (best, audio or a resolution like 1080p, 720p60, 2160p-hdr); falls back to the closest quality the video has")
 	windowMode := flag.String("window", "windowed", "window mode (windowed, fullscreen, borderless, maximized)")
+	sortOrder := flag.String("sort", "relevance", "search sort order (relevance, date, views, rating)")
+	duration := flag.String("duration", "", "search duration filter (short, medium, long)")
+	upload := flag.String("upload", "", "search upload date filter (hour, today, week, month, year)")
+	resultType := flag.String("type", "", "search result type (video, channel, playlist, movie)")
+	opts.registerNetworkFlags(flag.CommandLine)
+	language := flag.String("lang", "en", "language of YouTube's responses (en, pt, es, de, ...); dates and counts are understood in en, pt, es and de")
+	region := flag.String("region", "US", "two letter country code of the content region (US, BR, ES, DE, ...)")
+	features := flag.String("features", "", "comma separated search features (hd, 4k, subtitles, cc, live, hdr)")
+	enrich := flag.Bool("enrich", false, "fetch descriptions, keywords, likes and missing durations of the results on screen in the background")
 
 	flag.Usage = func() {
 		fmt.Println("\ngo-youtube [OPTIONS] <url | search term>")
 		fmt.Println("Examples:")
 		fmt.Println("  go-youtube \"lofi chill\"")
 		fmt.Println("  go-youtube -quality 720p -window fullscreen https://youtu.be/xxx")
+		fmt.Println("  go-youtube -sort date -upload week -duration long \"go conference\"")
+		fmt.Println("  go-youtube https://www.youtube.com/playlist?list=xxx")
+		fmt.Println("  go-youtube https://www.youtube.com/@handle")
 		fmt.Println("\nOptions:")
 		flag.PrintDefaults()
 	}
 
+	if err := loadConfig(flag.CommandLine); err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
 	flag.Parse()
 
 	opts.Debug = *debug
@@ -91,8 +127,19 @@ func ParseFlags() (*Options, error) {
 		}
 	})
 	opts.WindowMode = *windowMode
+	opts.Enrich = *enrich
 	IsDebug = opts.Debug
 
+	search, err := parseSearchOptions(*sortOrder, *duration, *upload, *resultType, *features)
+	if err != nil {
+		return nil, err
+	}
+	opts.Search = search
+
+	if opts.Language, opts.Region, err = parseLocale(*language, *region); err != nil {
+		return nil, err
+	}
+
 	if opts.Debug {
 		logger.InitLogger(opts.Debug)
 		logger.Debug("Debug mode enabled")
@@ -128,7 +175,11 @@ func ParseFlags() (*Options, error) {
 
 	opts.Input = input
 
-	if utils.IsYouTubeURL(input) {
+	if utils.IsPlaylistURL(input) {
+		opts.InputKind = InputPlaylistURL
+	} else if utils.IsChannelURL(input) {
+		opts.InputKind = InputChannelURL
+	} else if utils.IsYouTubeURL(input) {
 		opts.InputKind = InputYoutubeURL
 	} else {
 		opts.InputKind = InputSearchQuery
@@ -136,3 +187,131 @@ func ParseFlags() (*Options, error) {
 
 	return opts, nil
 }
+
+// parseLocale normalizes -lang to "pt" or "pt-BR" and -region to "BR"
+func parseLocale(language, region string) (string, string, error) {
+	base, variant, _ := strings.Cut(strings.TrimSpace(language), "-")
+	if !isLetters(base, 2, 3) || (variant != "" && !isAlphanumeric(variant)) {
+		return "", "", fmt.Errorf("invalid -lang %q, want a language code like en, pt or pt-BR", language)
+	}
+	language = strings.ToLower(base)
+	if variant != "" {
+		language += "-" + strings.ToUpper(variant)
+	}
+
+	region = strings.TrimSpace(region)
+	if region != "" && !isLetters(region, 2, 2) {
+		return "", "", fmt.Errorf("invalid -region %q, want a two letter country code like US or BR", region)
+	}
+	return language, strings.ToUpper(region), nil
+}
+
+func isLetters(s string, minLen, maxLen int) bool {
+	if len(s) < minLen || len(s) > maxLen {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func parseSearchOptions(sortOrder, duration, upload, resultType, features string) (models.SearchOptions, error) {
+	var opts models.SearchOptions
+	var err error
+
+	if opts.Sort, err = models.ParseSortOrder(sortOrder); err != nil {
+		return opts, err
+	}
+	if opts.Duration, err = models.ParseDurationFilter(duration); err != nil {
+		return opts, err
+	}
+	if opts.Upload, err = models.ParseUploadDate(upload); err != nil {
+		return opts, err
+	}
+	if opts.Type, err = models.ParseTypeFilter(resultType); err != nil {
+		return opts, err
+	}
+	if opts.Features, err = models.ParseSearchFeatures(features); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
+
+// IsCaptionsCommand reports whether the program was started as "go-youtube captions ..."
+func IsCaptionsCommand() bool {
+	return len(os.Args) > 1 && os.Args[1] == "captions"
+}
+
+func ParseCaptionsFlags(args []string) (*CaptionsOptions, error) {
+	fs := flag.NewFlagSet("captions", flag.ContinueOnError)
+
+	debug := fs.Bool("debug", false, "enable debug mode")
+	lang := fs.String("lang", "en", "caption language code (en, pt, es, ...)")
+	format := fs.String("format", "srt", "output format (srt, vtt, txt)")
+	output := fs.String("o", "", "write captions to this file instead of stdout")
+	list := fs.Bool("list", false, "list available caption tracks")
+	region := fs.String("region", "US", "two letter country code of the content region (US, BR, ES, DE, ...)")
+	var network NetworkOptions
+	network.registerNetworkFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Println("\ngo-youtube captions [OPTIONS] <url | video id>")
+		fmt.Println("Examples:")
+		fmt.Println("  go-youtube captions -list https://youtu.be/xxx")
+		fmt.Println("  go-youtube captions -lang pt -format vtt -o talk.vtt https://youtu.be/xxx")
+		fmt.Println("\nOptions:")
+		fs.PrintDefaults()
+	}
+
+	if err := loadConfig(fs); err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, ErrHelpRequested
+		}
+		return nil, err
+	}
+
+	IsDebug = *debug
+	if *debug {
+		logger.InitLogger(*debug)
+		logger.Debug("Debug mode enabled")
+	}
+
+	input := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if input == "" {
+		fs.Usage()
+		return nil, ErrNoInput
+	}
+
+	// YouTube answers in the language of the captions asked for, track names included
+	language, gl, err := parseLocale(*lang, *region)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CaptionsOptions{
+		Input:          utils.CleanYoutubeLink(input),
+		Lang:           *lang,
+		Format:         *format,
+		Output:         *output,
+		List:           *list,
+		Language:       language,
+		Region:         gl,
+		NetworkOptions: network,
+	}, nil
+}
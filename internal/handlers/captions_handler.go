@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Drack112/go-youtube/internal/api"
+	"github.com/Drack112/go-youtube/internal/flags"
+	"github.com/Drack112/go-youtube/pkg/logger"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+func RunCaptions(ctx context.Context, client api.Provider, opts *flags.CaptionsOptions) error {
+	id := utils.ExtractVideoID(opts.Input)
+	if id == "" {
+		return fmt.Errorf("invalid YouTube link or video id: %s", opts.Input)
+	}
+
+	video, err := client.GetVideo(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if len(video.Captions) == 0 {
+		return fmt.Errorf("no captions available for %s", id)
+	}
+
+	if opts.List {
+		fmt.Printf("Captions for %s\n\n", video.Title)
+		for _, t := range video.Captions {
+			kind := "manual"
+			if t.AutoGenerated {
+				kind = "auto-generated"
+			}
+			fmt.Printf("  %-8s %-30s %s\n", t.LanguageCode, t.LanguageName, kind)
+		}
+		return nil
+	}
+
+	track, ok := api.FindCaptionTrack(video.Captions, opts.Lang)
+	if !ok {
+		var codes []string
+		for _, t := range video.Captions {
+			codes = append(codes, t.LanguageCode)
+		}
+		return fmt.Errorf("no %q captions (available: %s)", opts.Lang, strings.Join(codes, ", "))
+	}
+
+	logger.Debug("[Captions] using track", "lang", track.LanguageCode, "auto", track.AutoGenerated)
+
+	cues, err := client.FetchCaptionCues(ctx, track)
+	if err != nil {
+		return err
+	}
+
+	text, err := api.FormatCaptions(cues, opts.Format)
+	if err != nil {
+		return err
+	}
+
+	if opts.Output == "" {
+		fmt.Print(text)
+		return nil
+	}
+
+	if err := os.WriteFile(opts.Output, []byte(text), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Saved %d captions to %s\n", len(cues), opts.Output)
+	return nil
+}
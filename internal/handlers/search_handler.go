@@ -1,42 +1,29 @@
 package handlers
 
 import (
-	"time"
+	"context"
+	"errors"
 
 	"github.com/Drack112/go-youtube/internal/api"
 	"github.com/Drack112/go-youtube/internal/flags"
-	"github.com/Drack112/go-youtube/internal/models"
 	"github.com/Drack112/go-youtube/internal/ui"
 
 	"github.com/Drack112/go-youtube/pkg/logger"
 )
 
-func SearchWithRetries(value *flags.Options) string {
-	maxAttempts := 3
-	var results []models.SearchResult
-	var err error
-
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		logger.Debug("[Handler] Search attempt", "attempt", attempt, "for", value.Input)
-
-		results, err = api.SearchVideos(value.Input)
-		if err == nil {
-			break
-		}
-
-		logger.Warn("[Handler]", "attempt", attempt, "error", err)
-		if attempt < maxAttempts {
-			time.Sleep(time.Second * time.Duration(attempt))
-		}
-	}
+// Search renders the results for value.Input. Transient failures are already retried by the client's transport.
+func Search(ctx context.Context, client api.Provider, value *flags.Options) string {
+	logger.Debug("[Handler] Search", "for", value.Input)
 
+	resp, err := client.SearchWithOptions(ctx, value.Input, value.Search, "")
 	if err != nil {
-		return ui.CreateErrorBox("Search Failed", err.Error())
+		logger.Warn("[Handler]", "error", err)
+		return ui.CreateErrorBox("Search Failed", err)
 	}
 
-	if len(results) == 0 {
-		return ui.CreateErrorBox("No Results", "No videos found for your search")
+	if resp == nil || len(resp.Results) == 0 {
+		return ui.CreateErrorBox("No Results", errors.New("no videos found for your search"))
 	}
 
-	return ui.CreateSearchResultsView(results)
+	return ui.CreateSearchResultsView(resp.Results)
 }
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SortOrder int
+
+const (
+	SortRelevance SortOrder = iota
+	SortRating
+	SortDate
+	SortViews
+)
+
+type UploadDate int
+
+const (
+	UploadAny UploadDate = iota
+	UploadLastHour
+	UploadToday
+	UploadThisWeek
+	UploadThisMonth
+	UploadThisYear
+)
+
+type DurationFilter int
+
+// values follow YouTube's own numbering, where "long" comes before "medium"
+const (
+	DurationAny DurationFilter = iota
+	DurationShort
+	DurationLong
+	DurationMedium
+)
+
+type TypeFilter int
+
+const (
+	TypeAny TypeFilter = iota
+	TypeVideo
+	TypeChannel
+	TypePlaylist
+	TypeMovie
+)
+
+type SearchFeatures struct {
+	HD              bool
+	FourK           bool
+	Subtitles       bool
+	CreativeCommons bool
+	Live            bool
+	HDR             bool
+}
+
+// SearchOptions holds the filters of the YouTube search page, encoded into the "sp" parameter by the api package
+type SearchOptions struct {
+	Sort     SortOrder
+	Upload   UploadDate
+	Duration DurationFilter
+	Type     TypeFilter
+	Features SearchFeatures
+}
+
+func (o SearchOptions) IsZero() bool {
+	return o == SearchOptions{}
+}
+
+var (
+	SortOrderNames  = []string{"relevance", "rating", "date", "views"}
+	UploadDateNames = []string{"any", "hour", "today", "week", "month", "year"}
+	DurationNames   = []string{"any", "short", "long", "medium"}
+	TypeNames       = []string{"any", "video", "channel", "playlist", "movie"}
+)
+
+func (s SortOrder) String() string      { return SortOrderNames[s] }
+func (u UploadDate) String() string     { return UploadDateNames[u] }
+func (d DurationFilter) String() string { return DurationNames[d] }
+func (t TypeFilter) String() string     { return TypeNames[t] }
+
+func parseName(kind string, value string, names []string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return 0, nil
+	}
+	for i, n := range names {
+		if n == value {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid %s %q (use %s)", kind, value, strings.Join(names, ", "))
+}
+
+func ParseSortOrder(value string) (SortOrder, error) {
+	// "upload_date" and "view_count" mirror the names used on youtube.com
+	switch strings.ToLower(value) {
+	case "upload_date", "newest":
+		return SortDate, nil
+	case "view_count", "popular":
+		return SortViews, nil
+	}
+	n, err := parseName("sort order", value, SortOrderNames)
+	return SortOrder(n), err
+}
+
+func ParseUploadDate(value string) (UploadDate, error) {
+	n, err := parseName("upload date", value, UploadDateNames)
+	return UploadDate(n), err
+}
+
+func ParseDurationFilter(value string) (DurationFilter, error) {
+	n, err := parseName("duration", value, DurationNames)
+	return DurationFilter(n), err
+}
+
+func ParseTypeFilter(value string) (TypeFilter, error) {
+	n, err := parseName("type", value, TypeNames)
+	return TypeFilter(n), err
+}
+
+// ParseSearchFeatures reads a comma separated list such as "hd,subtitles,live"
+func ParseSearchFeatures(value string) (SearchFeatures, error) {
+	var f SearchFeatures
+	for _, name := range strings.Split(value, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "":
+		case "hd":
+			f.HD = true
+		case "4k":
+			f.FourK = true
+		case "subtitles", "subs":
+			f.Subtitles = true
+		case "cc", "creative-commons":
+			f.CreativeCommons = true
+		case "live":
+			f.Live = true
+		case "hdr":
+			f.HDR = true
+		default:
+			return f, fmt.Errorf("invalid feature %q (use hd, 4k, subtitles, cc, live, hdr)", name)
+		}
+	}
+	return f, nil
+}
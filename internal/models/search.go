@@ -1,6 +1,19 @@
 package models
 
+import "time"
+
+type ResultKind int
+
+const (
+	ResultVideo ResultKind = iota
+	ResultPlaylist
+	ResultChannel
+	ResultMix
+	ResultMovie
+)
+
 type SearchResult struct {
+	Kind        ResultKind
 	ID          string
 	Title       string
 	URL         string
@@ -12,4 +25,41 @@ type SearchResult struct {
 	ChannelURl  string
 	IsLive      bool
 	IsShort     bool
+	VideoCount  int   // playlists and mixes
+	Subscribers int64 // channels only
+
+	ViewCount    int64
+	Published    string        // "3 years ago", as shown by YouTube
+	PublishedAge time.Duration // approximate age parsed from Published
+	PublishedAt  time.Time     // exact upload time, only channel feeds carry it
+	Snippet      string        // description excerpt, matched terms included
+	Verified     bool          // the channel (or the owner of the video) is verified
+	Artist       bool          // official artist channel
+	Badges       ResultBadges
+
+	// filled in from the video page by an enrichment pass, see api.Enrich
+	Enriched    bool
+	Description string
+	Keywords    []string
+	LikeCount   int64
+}
+
+// ResultBadges are the labels YouTube shows under a search result
+type ResultBadges struct {
+	FourK    bool
+	HDR      bool
+	Captions bool
+	New      bool
+}
+
+type Playlist struct {
+	ID          string
+	Title       string
+	Description string
+	URL         string
+	Thumbnail   string
+	OwnerName   string
+	OwnerID     string
+	VideoCount  int
+	ViewCount   int64
 }
@@ -35,10 +35,12 @@ type Video struct {
 }
 
 type ChannelInfo struct {
-	ID        string
-	Name      string
-	URL       string
-	Thumbnail string
+	ID          string
+	Name        string
+	Handle      string
+	URL         string
+	Thumbnail   string
+	Description string
 
 	SubscriberCount int64
 	Verified        bool
@@ -105,3 +107,21 @@ type MusicMetadata struct {
 	Producers []string
 	Song      string
 }
+
+type Comment struct {
+	ID         string
+	Author     string
+	AuthorID   string
+	Text       string
+	Published  string // "2 days ago", as shown by the source
+	LikeCount  int64
+	ReplyCount int
+	Pinned     bool
+	ByUploader bool // written by the channel that uploaded the video
+}
+
+type CaptionCue struct {
+	StartMs int
+	EndMs   int
+	Text    string
+}
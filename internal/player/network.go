@@ -0,0 +1,92 @@
+package player
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Drack112/go-youtube/pkg/logger"
+	"github.com/Drack112/go-youtube/pkg/utils"
+)
+
+// NetworkConfig is how mpv and yt-dlp reach YouTube, so playback and downloads go through the same
+// proxy, headers and cookies as the api client
+type NetworkConfig struct {
+	Proxy      string
+	Headers    http.Header // User-Agent included
+	CookieFile string      // Netscape cookies.txt
+}
+
+// Network applies to every playback and download started after it is set
+var Network NetworkConfig
+
+func (n NetworkConfig) userAgent() string {
+	if ua := n.Headers.Get("User-Agent"); ua != "" {
+		return ua
+	}
+	return utils.DefaultUserAgent
+}
+
+// extraHeaders returns the "Name: value" pairs besides the User-Agent, in a stable order
+func (n NetworkConfig) extraHeaders() [][2]string {
+	var pairs [][2]string
+	for _, name := range slices.Sorted(maps.Keys(n.Headers)) {
+		if http.CanonicalHeaderKey(name) == "User-Agent" {
+			continue
+		}
+		for _, v := range n.Headers[name] {
+			pairs = append(pairs, [2]string{name, v})
+		}
+	}
+	return pairs
+}
+
+// mpvArgs configures mpv and, through --ytdl-raw-options, the yt-dlp it runs to resolve the stream
+func (n NetworkConfig) mpvArgs() []string {
+	args := []string{"--user-agent=" + n.userAgent()}
+
+	for _, h := range n.extraHeaders() {
+		args = append(args,
+			"--http-header-fields-append="+h[0]+": "+h[1],
+			"--ytdl-raw-options-append=add-header="+h[0]+":"+h[1],
+		)
+	}
+
+	if n.Proxy != "" {
+		args = append(args, "--ytdl-raw-options-append=proxy="+n.Proxy)
+		// mpv fetches the stream itself and only speaks HTTP proxies
+		if strings.HasPrefix(n.Proxy, "http://") {
+			args = append(args, "--http-proxy="+n.Proxy)
+		} else {
+			logger.Warn("[Player] mpv cannot use this proxy for the stream itself, only yt-dlp will", "proxy", n.Proxy)
+		}
+	}
+
+	if n.CookieFile != "" {
+		args = append(args,
+			"--cookies",
+			"--cookies-file="+n.CookieFile,
+			"--ytdl-raw-options-append=cookies="+n.CookieFile,
+		)
+	}
+
+	return args
+}
+
+// ytdlpArgs configures a yt-dlp download
+func (n NetworkConfig) ytdlpArgs() []string {
+	args := []string{"--user-agent", n.userAgent()}
+
+	for _, h := range n.extraHeaders() {
+		args = append(args, "--add-header", h[0]+":"+h[1])
+	}
+	if n.Proxy != "" {
+		args = append(args, "--proxy", n.Proxy)
+	}
+	if n.CookieFile != "" {
+		args = append(args, "--cookies", n.CookieFile)
+	}
+
+	return args
+}
@@ -1,14 +1,18 @@
 package player
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/Drack112/go-youtube/pkg/logger"
+	"github.com/Drack112/go-youtube/pkg/utils"
 )
 
 type PlayerType string
@@ -38,14 +42,16 @@ func DetectYtDlp() string {
 	return ""
 }
 
-func StreamVideo(videoURL string, playerType PlayerType, quality string, windowMode string) error {
-	logger.Debug("[Player] Streaming video", "url", videoURL, "quality", quality, "window", windowMode)
+// StreamVideo plays videoURL in mpv, starting startSec seconds in (0 plays from the beginning).
+// Cancelling ctx closes the player.
+func StreamVideo(ctx context.Context, videoURL string, playerType PlayerType, quality string, windowMode string, startSec int) error {
+	logger.Debug("[Player] Streaming video", "url", videoURL, "quality", quality, "window", windowMode, "start", startSec)
 	var logFile *os.File
 	if logger.LogFile != nil {
 		logFile = logger.LogFile
 	}
 
-	cmd := buildMPVCommandWithOptions(videoURL, quality, windowMode)
+	cmd := buildMPVCommandWithOptions(ctx, videoURL, quality, windowMode, startSec)
 	// keep reference to allow external shutdown
 	currentMPVCmd = cmd
 
@@ -60,11 +66,15 @@ func StreamVideo(videoURL string, playerType PlayerType, quality string, windowM
 	logger.Debug("[Player] Executing command", "cmd", cmd.String())
 
 	if err := cmd.Run(); err != nil {
-		logger.Error("[Player] Failed to run player", "error", err)
 		// clear currentMPVCmd even on error
 		currentMPVCmd = nil
 		// close tail window if any
 		logger.CloseTailWindow()
+		if ctx.Err() != nil {
+			logger.Debug("[Player] Playback cancelled")
+			return ctx.Err()
+		}
+		logger.Error("[Player] Failed to run player", "error", err)
 		return fmt.Errorf("failed to run mpv: %w", err)
 	}
 
@@ -94,7 +104,7 @@ func StopCurrentPlayer() error {
 	return nil
 }
 
-func buildMPVCommandWithOptions(videoURL string, quality string, windowMode string) *exec.Cmd {
+func buildMPVCommandWithOptions(ctx context.Context, videoURL string, quality string, windowMode string, startSec int) *exec.Cmd {
 	ytdlp := DetectYtDlp()
 
 	args := []string{
@@ -116,12 +126,11 @@ func buildMPVCommandWithOptions(videoURL string, quality string, windowMode stri
 		args = append(args, "--force-window=yes")
 	}
 
+	args = append(args, Network.mpvArgs()...)
+
 	if ytdlp != "" {
 		args = append(args, "--script-opts=ytdl_hook-ytdl_path="+ytdlp)
 
-		userAgent := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
-		args = append(args, "--user-agent="+userAgent)
-
 		ytdlFormat := convertQualityToFormat(quality)
 		args = append(args, fmt.Sprintf("--ytdl-format=%s", ytdlFormat))
 	} else {
@@ -129,8 +138,22 @@ func buildMPVCommandWithOptions(videoURL string, quality string, windowMode stri
 		logger.Warn("[Player] yt-dlp not found - playback will likely fail. Install with: pip install yt-dlp")
 	}
 
+	if startSec > 0 {
+		args = append(args, fmt.Sprintf("--start=%d", startSec))
+	}
+
 	args = append(args, videoURL)
-	return exec.Command("mpv", args...)
+	cmd := exec.CommandContext(ctx, "mpv", args...)
+	terminateOnCancel(cmd)
+	return cmd
+}
+
+// terminateOnCancel asks the process to exit when its context is done and only kills it if it lingers
+func terminateOnCancel(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = 5 * time.Second
 }
 
 func convertQualityToFormat(quality string) string {
@@ -141,28 +164,46 @@ func convertQualityToFormat(quality string) string {
 		return "bestvideo+bestaudio/best"
 	case "worst", "lowest", "min":
 		return "worstvideo+worstaudio/worst"
-	case "1080p", "1080", "hd":
-		return "bestvideo[height<=1080]+bestaudio/best[height<=1080]"
-	case "720p", "720":
-		return "bestvideo[height<=720]+bestaudio/best[height<=720]"
-	case "480p", "480":
-		return "bestvideo[height<=480]+bestaudio/best[height<=480]"
-	case "360p", "360":
-		return "bestvideo[height<=360]+bestaudio/best[height<=360]"
 	case "audio", "audio-only":
 		return "bestaudio"
-	default:
-		return "bestvideo+bestaudio/best"
 	}
+
+	// any resolution label the video offers: "1080p", "720p60", "2160p", "1080p60-hdr", "hd"
+	if height := utils.ParseQualityHeight(quality); height > 0 {
+		quality, hdr := strings.CutSuffix(quality, "-hdr")
+		filter := fmt.Sprintf("[height<=%d]", height)
+		if _, fps, ok := strings.Cut(quality, "p"); ok && fps != "" {
+			if _, err := strconv.Atoi(fps); err == nil {
+				filter += fmt.Sprintf("[fps<=%s]", fps)
+			}
+		}
+		if hdr {
+			// the SDR stream of the same size is the fallback, HDR streams have no combined format
+			return fmt.Sprintf("bestvideo%s[dynamic_range^=HDR]+bestaudio/bestvideo%s+bestaudio/best%s", filter, filter, filter)
+		}
+		return fmt.Sprintf("bestvideo%s+bestaudio/best%s", filter, filter)
+	}
+
+	return "bestvideo+bestaudio/best"
+}
+
+// DownloadVideo saves videoURL with yt-dlp; cancelling ctx stops the download
+func DownloadVideo(ctx context.Context, videoURL string, container string, quality string, withThumb bool) error {
+	return runDownload(ctx, videoURL, "%(title)s.%(ext)s", container, quality, withThumb)
 }
 
-func DownloadVideo(videoURL string, container string, quality string, withThumb bool) error {
+// DownloadPlaylist downloads every entry of a playlist into a folder named after it
+func DownloadPlaylist(ctx context.Context, playlistURL string, container string, quality string) error {
+	return runDownload(ctx, playlistURL, "%(playlist_title)s/%(playlist_index)03d - %(title)s.%(ext)s", container, quality, false)
+}
+
+func runDownload(ctx context.Context, videoURL string, output string, container string, quality string, withThumb bool) error {
 	ytdlp := DetectYtDlp()
 	if ytdlp == "" {
 		return fmt.Errorf("yt-dlp or youtube-dl not found; install yt-dlp to enable downloads")
 	}
 
-	args := []string{"-o", "%(title)s.%(ext)s"}
+	args := append([]string{"-o", output}, Network.ytdlpArgs()...)
 
 	if withThumb {
 		args = append(args, "--write-thumbnail")
@@ -179,7 +220,8 @@ func DownloadVideo(videoURL string, container string, quality string, withThumb
 
 	args = append(args, videoURL)
 
-	cmd := exec.Command(ytdlp, args...)
+	cmd := exec.CommandContext(ctx, ytdlp, args...)
+	terminateOnCancel(cmd)
 
 	var out io.Writer
 	if logger.LogFile != nil {
@@ -190,5 +232,12 @@ func DownloadVideo(videoURL string, container string, quality string, withThumb
 	cmd.Stdout = out
 	cmd.Stderr = out
 
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			logger.Debug("[Player] Download cancelled", "url", videoURL)
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
 }
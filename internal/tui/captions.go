@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Drack112/go-youtube/internal/api"
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type captionsResultMsg struct {
+	text string
+	path string
+	view bool
+	err  error
+}
+
+func (m Model) captionTracks() []models.CaptionTrack {
+	if m.videoDetails == nil {
+		return nil
+	}
+	return m.videoDetails.Captions
+}
+
+func (m Model) updateCaptions(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.captionsBusy {
+		if keyMsg.String() == "esc" {
+			m.captions.stop()
+		}
+		return m, nil
+	}
+
+	tracks := m.captionTracks()
+
+	switch keyMsg.String() {
+	case "esc":
+		m.showCaptions = false
+		m.captionsMessage = ""
+	case "up", "k":
+		if m.captionCursor > 0 {
+			m.captionCursor--
+		}
+	case "down", "j":
+		if m.captionCursor < len(tracks)-1 {
+			m.captionCursor++
+		}
+	case "left", "h":
+		if m.captionFormat > 0 {
+			m.captionFormat--
+		}
+	case "right", "l":
+		if m.captionFormat < len(api.CaptionFormats)-1 {
+			m.captionFormat++
+		}
+	case "enter", "s":
+		if len(tracks) > 0 {
+			m.captionsBusy = true
+			m.captionsMessage = ""
+			return m, tea.Batch(m.fetchCaptionsCmd(tracks[m.captionCursor], false), m.spinner.Tick)
+		}
+	case "v":
+		if len(tracks) > 0 {
+			m.captionsBusy = true
+			m.captionsMessage = ""
+			return m, tea.Batch(m.fetchCaptionsCmd(tracks[m.captionCursor], true), m.spinner.Tick)
+		}
+	}
+
+	return m, nil
+}
+
+// fetchCaptionsCmd downloads a track and either saves it next to the binary or returns it as plain text for viewing
+func (m Model) fetchCaptionsCmd(track models.CaptionTrack, view bool) tea.Cmd {
+	format := api.CaptionFormats[m.captionFormat]
+	id := ""
+	if m.selectedVideo != nil {
+		id = m.selectedVideo.ID
+	}
+
+	ctx, _ := m.captions.start(m.ctx)
+	client := m.client
+	return func() tea.Msg {
+		cues, err := client.FetchCaptionCues(ctx, track)
+		if err != nil {
+			return captionsResultMsg{err: err}
+		}
+
+		if view {
+			text, err := api.FormatCaptions(cues, "txt")
+			return captionsResultMsg{text: text, view: true, err: err}
+		}
+
+		text, err := api.FormatCaptions(cues, format)
+		if err != nil {
+			return captionsResultMsg{err: err}
+		}
+
+		path := fmt.Sprintf("%s.%s.%s", id, track.LanguageCode, format)
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			return captionsResultMsg{err: err}
+		}
+		return captionsResultMsg{path: path}
+	}
+}
+
+func (m Model) renderCaptionsModal() string {
+	tracks := m.captionTracks()
+	lines := []string{lipgloss.NewStyle().Bold(true).Render("Captions"), ""}
+
+	if len(tracks) == 0 {
+		lines = append(lines, "No captions available for this video")
+	}
+
+	for i, t := range tracks {
+		name := t.LanguageName
+		if name == "" {
+			name = t.LanguageCode
+		}
+		line := fmt.Sprintf("%s (%s)", utils.TruncateText(name, 28), t.LanguageCode)
+		if t.AutoGenerated {
+			line += " [auto]"
+		}
+		if i == m.captionCursor {
+			line = "> " + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "", "Format: < "+api.CaptionFormats[m.captionFormat]+" >")
+
+	switch {
+	case m.captionsBusy:
+		lines = append(lines, "\nFetching captions... "+m.spinner.View()+"  (esc cancels)")
+	case m.captionsMessage != "":
+		lines = append(lines, "\n"+m.captionsMessage)
+	default:
+		lines = append(lines, "\nEnter save | v view | Esc close")
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	box := lipgloss.NewStyle().Width(52).Padding(1, 2).Border(lipgloss.RoundedBorder()).Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m Model) transcriptView() string {
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#64748B")).Render("↑/↓ scroll • esc close")
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), help),
+	)
+}
+
+func wrapTranscript(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+	return lipgloss.NewStyle().Width(width).Render(strings.TrimSpace(text))
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"context"
+
+	"github.com/Drack112/go-youtube/internal/api"
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/logger"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// enrichment tracks the background pass filling in the results of the list shown. Like tasks, the Model
+// keeps it behind a pointer so every copy shares it.
+type enrichment struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	run    int
+	queued map[string]bool
+}
+
+// reset abandons the pass over the previous list, its messages are stale from now on
+func (e *enrichment) reset(parent context.Context) {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.ctx, e.cancel = context.WithCancel(parent)
+	e.run++
+	e.queued = map[string]bool{}
+}
+
+// enrichedMsg carries the details of one video, and the channel the next one arrives on
+type enrichedMsg struct {
+	run   int
+	id    string
+	video *models.Video
+	next  <-chan enrichedMsg
+}
+
+// enrichVisible fetches the videos on the current page of the list that were not fetched yet. Their
+// details stream in one enrichedMsg at a time while the user keeps browsing.
+func (m *Model) enrichVisible() tea.Cmd {
+	if !m.opts.Enrich || m.state != stateList {
+		return nil
+	}
+	if m.enrich.ctx == nil {
+		m.enrich.reset(m.ctx)
+	}
+
+	visible := m.list.VisibleItems()
+	start, end := m.list.Paginator.GetSliceBounds(len(visible))
+	var batch []models.SearchResult
+	for _, li := range visible[start:end] {
+		it, ok := li.(item)
+		if !ok || it.result.Kind != models.ResultVideo || it.result.Enriched || m.enrich.queued[it.result.ID] {
+			continue
+		}
+		m.enrich.queued[it.result.ID] = true
+		batch = append(batch, it.result)
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	ctx, run, client := m.enrich.ctx, m.enrich.run, m.client
+	ch := make(chan enrichedMsg)
+	go func() {
+		defer close(ch)
+		api.Enrich(ctx, client, batch, api.DefaultEnrichOptions, func(id string, video *models.Video, err error) {
+			if err != nil {
+				logger.Debug("[Enrich] video details failed", "id", id, "error", err)
+				return
+			}
+			select {
+			case ch <- enrichedMsg{run: run, id: id, video: video, next: ch}:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return waitEnriched(ch)
+}
+
+// waitEnriched delivers the next message of a pass; a closed channel yields nil, which Bubble Tea drops
+func waitEnriched(ch <-chan enrichedMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// applyEnriched updates the results showing msg.video and keeps listening for the rest of the pass
+func (m *Model) applyEnriched(msg enrichedMsg) tea.Cmd {
+	// the pass was reset, its goroutine gives up on its own
+	if msg.run != m.enrich.run {
+		return nil
+	}
+
+	cmds := []tea.Cmd{waitEnriched(msg.next)}
+	for i := range m.results {
+		if m.results[i].ID != msg.id {
+			continue
+		}
+		api.EnrichResult(&m.results[i], msg.video)
+		if i < len(m.list.Items()) {
+			cmds = append(cmds, m.list.SetItem(i, item{result: m.results[i]}))
+		}
+	}
+	return tea.Batch(cmds...)
+}
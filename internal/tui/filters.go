@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/Drack112/go-youtube/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// filterRow is one line of the filter panel; step moves the value left (-1) or right (+1)
+type filterRow struct {
+	label string
+	value func(o *models.SearchOptions) string
+	step  func(o *models.SearchOptions, delta int)
+}
+
+func cycle(value, delta, count int) int {
+	return (value + delta + count) % count
+}
+
+func toggleRow(label string, field func(o *models.SearchOptions) *bool) filterRow {
+	return filterRow{
+		label: label,
+		value: func(o *models.SearchOptions) string {
+			if *field(o) {
+				return "on"
+			}
+			return "off"
+		},
+		step: func(o *models.SearchOptions, _ int) {
+			*field(o) = !*field(o)
+		},
+	}
+}
+
+var filterRows = []filterRow{
+	{
+		label: "Sort by",
+		value: func(o *models.SearchOptions) string { return o.Sort.String() },
+		step: func(o *models.SearchOptions, d int) {
+			o.Sort = models.SortOrder(cycle(int(o.Sort), d, len(models.SortOrderNames)))
+		},
+	},
+	{
+		label: "Upload date",
+		value: func(o *models.SearchOptions) string { return o.Upload.String() },
+		step: func(o *models.SearchOptions, d int) {
+			o.Upload = models.UploadDate(cycle(int(o.Upload), d, len(models.UploadDateNames)))
+		},
+	},
+	{
+		label: "Duration",
+		value: func(o *models.SearchOptions) string { return o.Duration.String() },
+		step: func(o *models.SearchOptions, d int) {
+			o.Duration = models.DurationFilter(cycle(int(o.Duration), d, len(models.DurationNames)))
+		},
+	},
+	{
+		label: "Type",
+		value: func(o *models.SearchOptions) string { return o.Type.String() },
+		step: func(o *models.SearchOptions, d int) {
+			o.Type = models.TypeFilter(cycle(int(o.Type), d, len(models.TypeNames)))
+		},
+	},
+	toggleRow("HD", func(o *models.SearchOptions) *bool { return &o.Features.HD }),
+	toggleRow("4K", func(o *models.SearchOptions) *bool { return &o.Features.FourK }),
+	toggleRow("HDR", func(o *models.SearchOptions) *bool { return &o.Features.HDR }),
+	toggleRow("Subtitles", func(o *models.SearchOptions) *bool { return &o.Features.Subtitles }),
+	toggleRow("Creative Commons", func(o *models.SearchOptions) *bool { return &o.Features.CreativeCommons }),
+	toggleRow("Live", func(o *models.SearchOptions) *bool { return &o.Features.Live }),
+}
+
+func (m Model) updateFilters(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch keyMsg.String() {
+	case "esc":
+		m.showFilters = false
+	case "up", "k":
+		if m.filterCursor > 0 {
+			m.filterCursor--
+		}
+	case "down", "j":
+		if m.filterCursor < len(filterRows)-1 {
+			m.filterCursor++
+		}
+	case "left", "h":
+		filterRows[m.filterCursor].step(&m.pendingFilters, -1)
+	case "right", "l", " ":
+		filterRows[m.filterCursor].step(&m.pendingFilters, 1)
+	case "r":
+		m.pendingFilters = models.SearchOptions{}
+	case "enter":
+		m.showFilters = false
+		m.searchOpts = m.pendingFilters
+		m.loadingLabel = "Searching for: " + m.opts.Input
+		m.state = stateLoading
+		return m, tea.Batch(m.spinner.Tick, m.performSearch())
+	}
+
+	return m, nil
+}
+
+func (m Model) renderFilterPanel() string {
+	lines := []string{lipgloss.NewStyle().Bold(true).Render("Search filters"), ""}
+
+	for i, row := range filterRows {
+		line := fmt.Sprintf("%-18s < %s >", row.label, row.value(&m.pendingFilters))
+		if i == m.filterCursor {
+			line = "> " + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "", "←/→ change | r reset | Enter search | Esc cancel")
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	box := lipgloss.NewStyle().Width(54).Padding(1, 2).Border(lipgloss.RoundedBorder()).Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// activeFilters summarizes the applied filters for the list title
+func activeFilters(o models.SearchOptions) string {
+	parts := ""
+	for _, row := range filterRows {
+		v := row.value(&o)
+		if v == "off" || v == "any" || v == "relevance" {
+			continue
+		}
+		if parts != "" {
+			parts += ", "
+		}
+		if v == "on" {
+			parts += row.label
+		} else {
+			parts += row.label + ": " + v
+		}
+	}
+	return parts
+}
@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/Drack112/go-youtube/pkg/utils"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -12,16 +14,52 @@ type item struct {
 	result models.SearchResult
 }
 
+// FilterValue lets the list filter match keywords too, once enrichment found them
 func (i item) FilterValue() string {
+	if len(i.result.Keywords) > 0 {
+		return i.result.Title + " " + strings.Join(i.result.Keywords, " ")
+	}
 	return i.result.Title
 }
 func (i item) Title() string {
 	var badges []string
 
+	switch i.result.Kind {
+	case models.ResultPlaylist, models.ResultMix:
+		if i.result.Kind == models.ResultMix {
+			badges = append(badges, "MIX")
+		} else {
+			badges = append(badges, "PLAYLIST")
+		}
+		if i.result.VideoCount > 0 {
+			badges = append(badges, strconv.Itoa(i.result.VideoCount)+" videos")
+		}
+	case models.ResultChannel:
+		badges = append(badges, "CHANNEL")
+	case models.ResultMovie:
+		badges = append(badges, "MOVIE")
+	}
+
 	if i.result.IsLive {
 		badges = append(badges, "LIVE")
 	}
 
+	if i.result.Badges.New {
+		badges = append(badges, "NEW")
+	}
+
+	if i.result.Badges.FourK {
+		badges = append(badges, "4K")
+	}
+
+	if i.result.Badges.HDR {
+		badges = append(badges, "HDR")
+	}
+
+	if i.result.Badges.Captions {
+		badges = append(badges, "CC")
+	}
+
 	if i.result.IsShort {
 		badges = append(badges, "SHORT")
 	}
@@ -40,17 +78,43 @@ func (i item) Title() string {
 
 func (i item) Description() string {
 	var parts []string
-	parts = append(parts, i.result.URL)
 
 	if i.result.ChannelName != "" {
-		parts = append(parts, i.result.ChannelName)
+		channel := i.result.ChannelName
+		switch {
+		case i.result.Artist:
+			channel += " ♪"
+		case i.result.Verified:
+			channel += " ✓"
+		}
+		parts = append(parts, channel)
+	}
+
+	if i.result.Kind == models.ResultChannel && i.result.Subscribers > 0 {
+		parts = append(parts, utils.FormatCount(i.result.Subscribers)+" subscribers")
 	}
 
-	return strings.Join(parts, " * ")
+	if i.result.ViewCount > 0 {
+		parts = append(parts, utils.FormatViews(i.result.ViewCount))
+	}
+
+	if i.result.LikeCount > 0 {
+		parts = append(parts, utils.FormatCount(i.result.LikeCount)+" likes")
+	}
+
+	if published := utils.PublishedLabel(i.result.Published, i.result.PublishedAge); published != "" {
+		parts = append(parts, published)
+	}
+
+	parts = append(parts, i.result.URL)
+
+	// the delegate shows two description lines, the snippet goes on the second one
+	return strings.Join(parts, " * ") + "\n" + i.result.Snippet
 }
 
 func NewItemDelegate() list.DefaultDelegate {
 	d := list.NewDefaultDelegate()
+	d.SetHeight(3)
 
 	d.Styles.SelectedTitle = d.Styles.SelectedTitle.
 		Foreground(lipgloss.Color("#8B5CF6")).
@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"github.com/Drack112/go-youtube/internal/api"
+	"github.com/Drack112/go-youtube/internal/models"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// listSnapshot keeps a list page so esc can return to it after opening a channel or playlist
+type listSnapshot struct {
+	results           []models.SearchResult
+	continuationToken string
+	hasMore           bool
+	playlist          *models.Playlist
+	channel           *models.ChannelInfo
+	channelTab        api.ChannelTab
+	index             int
+}
+
+// selectedIndex returns the position in m.results of the highlighted row. With a filter applied the list
+// only shows the matches, so the row's position on screen says nothing about m.results.
+func (m *Model) selectedIndex() (int, bool) {
+	if m.list.SelectedItem() == nil {
+		return 0, false
+	}
+	idx := m.list.GlobalIndex()
+	return idx, idx >= 0 && idx < len(m.results)
+}
+
+func (m *Model) pushHistory() {
+	index, _ := m.selectedIndex()
+
+	m.history = append(m.history, listSnapshot{
+		results:           m.results,
+		continuationToken: m.continuationToken,
+		hasMore:           m.hasMore,
+		playlist:          m.playlist,
+		channel:           m.channel,
+		channelTab:        m.channelTab,
+		index:             index,
+	})
+}
+
+// goBack restores the previous list page; it reports false when there is nothing to go back to
+func (m *Model) goBack() bool {
+	if len(m.history) == 0 {
+		return false
+	}
+
+	prev := m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+
+	m.results = prev.results
+	m.continuationToken = prev.continuationToken
+	m.hasMore = prev.hasMore
+	m.playlist = prev.playlist
+	m.channel = prev.channel
+	m.channelTab = prev.channelTab
+	m.state = stateList
+	m.selectedVideo = nil
+	m.load.stop()
+	m.isLoadingMore = false
+	m.enrich.reset(m.ctx)
+
+	// the index points into the whole page, a filter typed on the page being left would shift it
+	m.list.ResetFilter()
+	m.refreshList()
+	m.list.Select(prev.index)
+	return true
+}
+
+// refreshList syncs the list widget with the current results and page kind
+func (m *Model) refreshList() {
+	items := make([]list.Item, len(m.results))
+	for i, result := range m.results {
+		items[i] = item{result: result}
+	}
+	m.list.SetItems(items)
+	m.list.Title = m.listTitle()
+
+	helpKeys := searchHelpKeys
+	switch {
+	case m.playlist != nil:
+		helpKeys = playlistHelpKeys
+	case m.channel != nil:
+		helpKeys = channelHelpKeys
+	}
+	m.list.AdditionalShortHelpKeys = helpKeys
+	m.list.AdditionalFullHelpKeys = helpKeys
+}
+
+// openChannel jumps to the channel of a result, remembering the current page
+func (m *Model) openChannel(channelID string) tea.Cmd {
+	if channelID == "" {
+		return nil
+	}
+
+	m.pushHistory()
+	if m.state != stateList {
+		// coming from the detail view, going back returns to the list the video was picked from
+		m.selectedVideo = nil
+		m.videoDetails = nil
+	}
+
+	m.loadingLabel = "Loading channel..."
+	m.state = stateLoading
+	return tea.Batch(m.spinner.Tick, m.fetchChannel(channelID, api.ChannelTabVideos))
+}
+
+func (m *Model) openPlaylist(playlistID string) tea.Cmd {
+	m.pushHistory()
+	m.loadingLabel = "Loading playlist..."
+	m.state = stateLoading
+	return tea.Batch(m.spinner.Tick, m.fetchPlaylistByID(playlistID))
+}
+
+// switchChannelTab moves to the next channel tab in place, without adding a history entry
+func (m *Model) switchChannelTab() tea.Cmd {
+	if m.channel == nil {
+		return nil
+	}
+
+	next := api.ChannelTabs[0]
+	for i, t := range api.ChannelTabs {
+		if t == m.channelTab {
+			next = api.ChannelTabs[(i+1)%len(api.ChannelTabs)]
+			break
+		}
+	}
+
+	m.loadingLabel = "Loading " + next.Label() + "..."
+	m.state = stateLoading
+	return tea.Batch(m.spinner.Tick, m.fetchChannel(m.channel.ID, next))
+}
+
+func (m *Model) fetchChannel(ref string, tab api.ChannelTab) tea.Cmd {
+	ctx, loadID := m.load.start(m.ctx)
+	client := m.client
+	return func() tea.Msg {
+		resp, err := client.GetChannel(ctx, ref, tab, "")
+		if err != nil {
+			return searchResultsMsg{loadID: loadID, err: err}
+		}
+		return searchResultsMsg{
+			loadID:            loadID,
+			results:           resp.Results,
+			continuationToken: resp.ContinuationToken,
+			hasMore:           resp.HasMore,
+			channel:           resp.Channel,
+			channelTab:        resp.Tab,
+		}
+	}
+}
+
+func (m *Model) fetchPlaylistByID(id string) tea.Cmd {
+	ctx, loadID := m.load.start(m.ctx)
+	client := m.client
+	return func() tea.Msg {
+		resp, err := client.GetPlaylist(ctx, id, "")
+		if err != nil {
+			return searchResultsMsg{loadID: loadID, err: err}
+		}
+		return searchResultsMsg{
+			loadID:            loadID,
+			results:           resp.Results,
+			continuationToken: resp.ContinuationToken,
+			hasMore:           resp.HasMore,
+			playlist:          resp.Playlist,
+		}
+	}
+}
+
+func searchHelpKeys() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "view details")),
+		key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "load more")),
+		key.NewBinding(key.WithKeys("@"), key.WithHelp("@", "open channel")),
+		key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "filters")),
+	}
+}
+
+func playlistHelpKeys() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "view details")),
+		key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "load more")),
+		key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "play all")),
+		key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "download all")),
+		key.NewBinding(key.WithKeys("@"), key.WithHelp("@", "open channel")),
+	}
+}
+
+func channelHelpKeys() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open")),
+		key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "load more")),
+		key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next tab")),
+	}
+}
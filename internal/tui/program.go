@@ -1,13 +1,15 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Drack112/go-youtube/internal/api"
 	"github.com/Drack112/go-youtube/internal/flags"
 	"github.com/Drack112/go-youtube/internal/models"
 	"github.com/Drack112/go-youtube/internal/player"
-	"github.com/charmbracelet/bubbles/key"
+	"github.com/Drack112/go-youtube/internal/ui"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -27,8 +29,25 @@ const (
 type Model struct {
 	state             state
 	opts              *flags.Options
+	client            api.Provider
+	ctx               context.Context // cancelled when the program quits
+	quit              context.CancelFunc
+	load              *task // search, channel and playlist pages
+	details           *task
+	download          *task
+	captions          *task
+	enrich            *enrichment
 	results           []models.SearchResult
 	selectedVideo     *models.SearchResult
+	playlist          *models.Playlist
+	channel           *models.ChannelInfo
+	channelTab        api.ChannelTab
+	history           []listSnapshot
+	loadingLabel      string
+	videoDetails      *models.Video
+	chapterCursor     int
+	detailsLoading    bool
+	detailsErr        error
 	err               error
 	continuationToken string
 	hasMore           bool
@@ -44,28 +63,50 @@ type Model struct {
 
 	showDownload       bool
 	downloadCursor     int
-	downloadQualities  []string
+	downloadQualities  []api.QualityOption
 	downloadContainers []string
 	selectedQuality    int
 	selectedContainer  int
 	downloadWithThumb  bool
 	downloadInProgress bool
 	downloadMessage    string
+
+	searchOpts     models.SearchOptions
+	showFilters    bool
+	filterCursor   int
+	pendingFilters models.SearchOptions
+
+	showCaptions    bool
+	showTranscript  bool
+	captionCursor   int
+	captionFormat   int
+	captionsBusy    bool
+	captionsMessage string
 }
 
 type searchResultsMsg struct {
+	loadID            int
 	results           []models.SearchResult
 	continuationToken string
 	hasMore           bool
 	err               error
 	isLoadMore        bool
+	playlist          *models.Playlist
+	channel           *models.ChannelInfo
+	channelTab        api.ChannelTab
+}
+
+type videoDetailsMsg struct {
+	id    string
+	video *models.Video
+	err   error
 }
 
 type downloadResultMsg struct {
 	err error
 }
 
-func NewModel(opts *flags.Options) Model {
+func NewModel(opts *flags.Options, client api.Provider) Model {
 	detectedPlayer, err := player.DetectAvailablePlayer()
 	playerTypeStr := ""
 	if err == nil {
@@ -81,30 +122,28 @@ func NewModel(opts *flags.Options) Model {
 	l.Title = "[?] Search Results"
 	l.Styles.Title = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B5CF6")).Bold(true).Padding(0, 1)
 
-	helpKeys := func() []key.Binding {
-		return []key.Binding{
-			key.NewBinding(
-				key.WithKeys("enter"),
-				key.WithHelp("enter", "view details"),
-			),
-			key.NewBinding(
-				key.WithKeys("m"),
-				key.WithHelp("m", "load more"),
-			),
-		}
-	}
+	l.AdditionalShortHelpKeys = searchHelpKeys
+	l.AdditionalFullHelpKeys = searchHelpKeys
 
-	l.AdditionalShortHelpKeys = helpKeys
-	l.AdditionalFullHelpKeys = helpKeys
+	ctx, quit := context.WithCancel(context.Background())
 
 	return Model{
 		state:              stateLoading,
 		opts:               opts,
+		client:             client,
+		ctx:                ctx,
+		quit:               quit,
+		load:               &task{},
+		details:            &task{},
+		download:           &task{},
+		captions:           &task{},
+		enrich:             &enrichment{},
 		spinner:            s,
 		list:               l,
 		playerType:         playerTypeStr,
-		downloadQualities:  []string{"best", "1080p", "720p", "480p", "360p", "audio"},
+		downloadQualities:  api.DefaultQualities,
 		downloadContainers: []string{"mp4", "mkv", "webm"},
+		searchOpts:         opts.Search,
 	}
 }
 
@@ -119,17 +158,38 @@ func (m Model) Init() tea.Cmd {
 		return tea.Batch(m.spinner.Tick, m.fetchVideoDetails())
 	}
 
+	if m.opts.InputKind == flags.InputPlaylistURL {
+		return tea.Batch(m.spinner.Tick, m.fetchPlaylist())
+	}
+
+	if m.opts.InputKind == flags.InputChannelURL {
+		return tea.Batch(m.spinner.Tick, m.fetchChannel(m.opts.Input, api.ChannelTabVideos))
+	}
+
 	return tea.Batch(m.spinner.Tick, m.performSearch())
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch d := msg.(type) {
 	case downloadResultMsg:
+		m.download.stop()
+		if errors.Is(d.err, context.Canceled) {
+			m.downloadInProgress = false
+			if m.showDownload {
+				m.downloadMessage = "Download cancelled"
+				return m, nil
+			}
+			return m, m.list.NewStatusMessage("Download cancelled")
+		}
+
 		// clear console to remove any stray log output and close modal
 		fmt.Print("\033[H\033[2J")
 		m.downloadInProgress = false
 		m.downloadMessage = ""
 		m.showDownload = false
+		if d.err == nil && m.state == stateList && m.playlist != nil {
+			return m, m.list.NewStatusMessage("Playlist download finished")
+		}
 		if d.err != nil {
 			// optionally write a short message in the list area (could be expanded)
 			m.err = fmt.Errorf("download failed: %w", d.err)
@@ -152,6 +212,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.showDownload && m.downloadInProgress {
 				return m, nil
 			}
+			m.quit()
 			_ = player.StopCurrentPlayer()
 			return m, tea.Quit
 		case "q":
@@ -159,38 +220,106 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.showDownload && m.downloadInProgress {
 				return m, nil
 			}
-			if m.state == stateList || m.state == stateError {
+			if (m.state == stateList && !m.showFilters) || m.state == stateError || m.state == stateLoading {
+				m.quit()
 				_ = player.StopCurrentPlayer()
 				return m, tea.Quit
 			}
 		case "esc":
 			switch m.state {
+			case stateLoading:
+				// abandon the page being loaded and return to the one it was opened from
+				m.load.stop()
+				if m.goBack() {
+					return m, tea.Batch(m.list.NewStatusMessage("Cancelled"), m.enrichVisible())
+				}
+				m.quit()
+				return m, tea.Quit
 			case stateDetail:
+				// modals handle esc themselves
+				if m.showDownload || m.showCaptions || m.showTranscript {
+					break
+				}
+				m.details.stop()
 				m.state = stateList
 				m.selectedVideo = nil
+				m.videoDetails = nil
+				m.detailsLoading = false
 				return m, nil
 			case stateError:
+				m.quit()
 				_ = player.StopCurrentPlayer()
 				return m, tea.Quit
 			}
 		}
 
+	case enrichedMsg:
+		return m, m.applyEnriched(msg)
+
+	case captionsResultMsg:
+		m.captions.stop()
+		m.captionsBusy = false
+		switch {
+		case errors.Is(msg.err, context.Canceled):
+			m.captionsMessage = "Cancelled"
+		case msg.err != nil:
+			m.captionsMessage = "Failed: " + msg.err.Error()
+		case msg.view:
+			m.showCaptions = false
+			m.showTranscript = true
+			m.viewport.SetContent(wrapTranscript(msg.text, m.viewport.Width-2))
+			m.viewport.GotoTop()
+		default:
+			m.captionsMessage = "Saved to " + msg.path
+		}
+		return m, nil
+
+	case videoDetailsMsg:
+		// ignore late responses for a video that is no longer open
+		if m.selectedVideo == nil || m.selectedVideo.ID != msg.id {
+			return m, nil
+		}
+		m.details.stop()
+		m.detailsLoading = false
+		m.detailsErr = msg.err
+		if msg.err == nil {
+			m.videoDetails = msg.video
+			m.downloadQualities = api.AvailableQualities(msg.video)
+			if m.selectedQuality >= len(m.downloadQualities) {
+				m.selectedQuality = 0
+			}
+		}
+		m.viewport.SetContent(m.createDetailView())
+		return m, nil
+
 	case searchResultsMsg:
+		// answers of a cancelled or superseded load are dropped
+		if !m.load.current(msg.loadID) {
+			return m, nil
+		}
+		m.load.stop()
 		m.isLoadingMore = false
 		if msg.err != nil {
+			// a failed channel/playlist jump returns to the page it was opened from
+			if !msg.isLoadMore && m.state == stateLoading && m.goBack() {
+				if title, _ := ui.DescribeError(msg.err); title != "" {
+					return m, m.list.NewStatusMessage("Error: " + title)
+				}
+				return m, m.list.NewStatusMessage("Error: " + msg.err.Error())
+			}
 			m.state = stateError
 			m.err = msg.err
 			return m, nil
 		}
 
 		// If input was a direct YouTube URL, open the detail view immediately
-		if m.opts != nil && m.opts.InputKind == flags.InputYoutubeURL && len(msg.results) > 0 {
+		if m.opts != nil && m.opts.InputKind == flags.InputYoutubeURL && len(msg.results) > 0 && len(m.history) == 0 {
 			// store results in model and select first item safely
 			m.results = msg.results
 			m.selectedVideo = &m.results[0]
 			m.state = stateDetail
 			m.viewport.SetContent(m.createDetailView())
-			return m, nil
+			return m, m.openDetails()
 		}
 
 		// Deduplicate the data of the youtube after trigger the "Load More" option
@@ -216,24 +345,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.results = append(m.results, toAdd...)
 			}
 		} else {
+			m.enrich.reset(m.ctx)
 			m.results = msg.results
+			m.playlist = msg.playlist
+			m.channel = msg.channel
+			m.channelTab = msg.channelTab
 		}
-	
 
 		m.continuationToken = msg.continuationToken
 		m.hasMore = msg.hasMore
 		m.state = stateList
 
-		items := make([]list.Item, len(m.results))
-		for i, result := range m.results {
-			items[i] = item{result: result}
-		}
-		m.list.SetItems(items)
-
-		if m.hasMore {
-			m.list.Title = fmt.Sprintf("[?] Search Results (%d results - Press 'm' for more)", len(m.results))
-		} else {
-			m.list.Title = fmt.Sprintf("[?] Search Results (%d results)", len(m.results))
+		m.refreshList()
+		if !msg.isLoadMore {
+			m.list.Select(0)
 		}
 	}
 
@@ -242,29 +367,123 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case stateLoading:
 		m.spinner, cmd = m.spinner.Update(msg)
 	case stateList:
+		if m.showFilters {
+			if keyMsg, ok := msg.(tea.KeyMsg); ok {
+				return m.updateFilters(keyMsg)
+			}
+			return m, nil
+		}
+
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
 			case "enter":
-				if idx := m.list.Index(); idx >= 0 {
-					if idx < len(m.results) {
-						m.selectedVideo = &m.results[idx]
-						m.state = stateDetail
-						m.viewport.SetContent(m.createDetailView())
-						return m, nil
+				// while the filter is typed, enter applies it
+				if idx, ok := m.selectedIndex(); ok && m.list.FilterState() != list.Filtering {
+					switch m.results[idx].Kind {
+					case models.ResultPlaylist:
+						return m, m.openPlaylist(m.results[idx].ID)
+					case models.ResultChannel:
+						return m, m.openChannel(m.results[idx].ID)
+					case models.ResultMix:
+						if m.playerType == "" {
+							return m, m.list.NewStatusMessage("No video player found")
+						}
+						return m, tea.Sequence(
+							tea.ExitAltScreen,
+							m.playMix(m.results[idx].URL),
+							tea.EnterAltScreen,
+						)
 					}
+
+					m.selectedVideo = &m.results[idx]
+					m.state = stateDetail
+					m.viewport.SetContent(m.createDetailView())
+					return m, m.openDetails()
 				}
 			case "m", "M":
 				if m.hasMore && !m.isLoadingMore {
 					m.isLoadingMore = true
 					return m, m.loadMoreResults()
 				}
+			case "@":
+				if idx, ok := m.selectedIndex(); ok && m.list.FilterState() != list.Filtering {
+					return m, m.openChannel(m.results[idx].ChannelID)
+				}
+			case "f":
+				if m.isSearchPage() && m.list.FilterState() != list.Filtering {
+					m.showFilters = true
+					m.filterCursor = 0
+					m.pendingFilters = m.searchOpts
+					return m, nil
+				}
+			case "tab":
+				if m.channel != nil && m.list.FilterState() != list.Filtering {
+					return m, m.switchChannelTab()
+				}
+			case "esc":
+				if m.list.FilterState() != list.Unfiltered {
+					break
+				}
+				if m.downloadInProgress {
+					m.download.stop()
+					return m, nil
+				}
+				if m.isLoadingMore {
+					m.load.stop()
+					m.isLoadingMore = false
+					return m, m.list.NewStatusMessage("Cancelled")
+				}
+				if m.goBack() {
+					return m, m.enrichVisible()
+				}
+			case "a":
+				if m.playlist != nil && m.playerType != "" && m.list.FilterState() != list.Filtering {
+					return m, tea.Sequence(
+						tea.ExitAltScreen,
+						m.playPlaylist(),
+						tea.EnterAltScreen,
+					)
+				}
+			case "D":
+				if m.playlist != nil && !m.downloadInProgress && m.list.FilterState() != list.Filtering {
+					m.downloadInProgress = true
+					return m, tea.Batch(
+						m.list.NewStatusMessage(fmt.Sprintf("Downloading %d videos...", m.playlist.VideoCount)),
+						m.downloadPlaylistCmd(),
+					)
+				}
 			}
 		}
 		m.list, cmd = m.list.Update(msg)
+		// paging, filtering and resizing bring other results on screen
+		cmd = tea.Batch(cmd, m.enrichVisible())
 	case stateDetail:
+		if m.showTranscript {
+			if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "esc" {
+				m.showTranscript = false
+				return m, nil
+			}
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+
+		if m.showCaptions {
+			if keyMsg, ok := msg.(tea.KeyMsg); ok {
+				return m.updateCaptions(keyMsg)
+			}
+			if m.captionsBusy {
+				m.spinner, cmd = m.spinner.Update(msg)
+			}
+			return m, cmd
+		}
+
 		if m.showDownload {
 			if keyMsg, ok := msg.(tea.KeyMsg); ok {
 				if m.downloadInProgress {
+					if keyMsg.String() == "esc" {
+						m.download.stop()
+						return m, nil
+					}
 					var innerCmd tea.Cmd
 					m.spinner, innerCmd = m.spinner.Update(msg)
 					return m, innerCmd
@@ -328,11 +547,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if !m.downloadInProgress {
 						// start download
 						m.downloadInProgress = true
-						quality := m.downloadQualities[m.selectedQuality]
+						quality := m.downloadQualities[m.selectedQuality].Quality
 						container := m.downloadContainers[m.selectedContainer]
-						// if flag provided for quality, respect it and only use flag value
+						// if flag provided for quality, respect it as long as the video offers it
 						if m.opts.QualityProvided {
-							quality = m.opts.Quality
+							quality = m.resolvedFlagQuality().Quality
 						}
 						return m, tea.Batch(m.startDownloadCmd(m.selectedVideo.URL, container, quality, m.downloadWithThumb), m.spinner.Tick)
 					}
@@ -352,6 +571,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						tea.EnterAltScreen,
 					)
 				}
+			// chapters get their own keys, up/down and j/k keep scrolling the view
+			case "]":
+				if m.videoDetails != nil && m.chapterCursor < len(m.videoDetails.Chapters)-1 {
+					m.chapterCursor++
+					return m, nil
+				}
+			case "[":
+				// moving before the first chapter goes back to "play from the beginning"
+				if m.chapterCursor >= 0 {
+					m.chapterCursor--
+					return m, nil
+				}
+			case "@":
+				if m.selectedVideo != nil {
+					channelID := m.selectedVideo.ChannelID
+					if channelID == "" && m.videoDetails != nil {
+						channelID = m.videoDetails.Channel.ID
+					}
+					return m, m.openChannel(channelID)
+				}
+			case "c", "C":
+				if m.videoDetails != nil {
+					m.showCaptions = true
+					m.captionsMessage = ""
+					m.captionCursor = 0
+					if track, ok := api.FindCaptionTrack(m.videoDetails.Captions, "en"); ok {
+						for i, t := range m.videoDetails.Captions {
+							if t == track {
+								m.captionCursor = i
+								break
+							}
+						}
+					}
+					return m, nil
+				}
 			case "d", "D":
 				if m.selectedVideo != nil {
 					// open download modal
@@ -359,8 +613,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// initialize selections
 					// set quality index to match opts.Quality if present
 					m.selectedQuality = 0
+					resolved, _ := api.ResolveQuality(m.downloadQualities, m.opts.Quality)
 					for i, q := range m.downloadQualities {
-						if q == m.opts.Quality {
+						if q == resolved {
 							m.selectedQuality = i
 							break
 						}
@@ -389,29 +644,71 @@ func (m Model) View() string {
 	case stateLoading:
 		return m.loadingView()
 	case stateList:
+		if m.showFilters {
+			return m.renderFilterPanel()
+		}
 		return m.listView()
 	case stateDetail:
+		if m.showTranscript {
+			return m.transcriptView()
+		}
+		if m.showCaptions {
+			return m.renderCaptionsModal()
+		}
 		if m.showDownload {
 			return m.renderDownloadModal()
 		}
 		return m.detailView()
+	case stateError:
+		return m.errorView()
 	default:
 		return "Unknown state"
 	}
 }
 
+// openDetails resets the metadata of the previous video and starts loading the selected one
+func (m *Model) openDetails() tea.Cmd {
+	m.videoDetails = nil
+	m.detailsErr = nil
+	m.chapterCursor = -1
+	m.showCaptions = false
+	m.showTranscript = false
+	m.downloadQualities = api.DefaultQualities
+	if m.selectedVideo == nil || m.selectedVideo.ID == "" {
+		m.detailsLoading = false
+		return nil
+	}
+	m.detailsLoading = true
+	return m.fetchVideoMetadata(m.selectedVideo.ID)
+}
+
+func (m *Model) fetchVideoMetadata(id string) tea.Cmd {
+	ctx, _ := m.details.start(m.ctx)
+	client := m.client
+	return func() tea.Msg {
+		video, err := client.GetVideo(ctx, id)
+		return videoDetailsMsg{id: id, video: video, err: err}
+	}
+}
+
 func (m *Model) fetchVideoDetails() tea.Cmd {
+	ctx, loadID := m.load.start(m.ctx)
 	return func() tea.Msg {
-		results, err := api.SearchVideos(m.opts.Input)
+		resp, err := m.client.SearchWithOptions(ctx, m.opts.Input, models.SearchOptions{}, "")
 		if err != nil {
-			return searchResultsMsg{err: err}
+			return searchResultsMsg{loadID: loadID, err: err}
 		}
 
+		var results []models.SearchResult
+		if resp != nil {
+			results = resp.Results
+		}
 		if len(results) == 0 {
-			return searchResultsMsg{err: fmt.Errorf("no video found for URL: %s", m.opts.Input)}
+			return searchResultsMsg{loadID: loadID, err: fmt.Errorf("no video found for URL: %s", m.opts.Input)}
 		}
 
 		return searchResultsMsg{
+			loadID:  loadID,
 			results: results,
 			hasMore: false,
 		}
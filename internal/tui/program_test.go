@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/Drack112/go-youtube/internal/api"
+	"github.com/Drack112/go-youtube/internal/flags"
+	"github.com/Drack112/go-youtube/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var mixedResults = []models.SearchResult{
+	{Kind: models.ResultVideo, ID: "vid-go", Title: "Go concurrency patterns", ChannelID: "UCgo"},
+	{Kind: models.ResultChannel, ID: "UCrust", Title: "Rust channel", ChannelID: "UCrust"},
+	{Kind: models.ResultPlaylist, ID: "PLgophers", Title: "Gophers playlist", ChannelID: "UCplaylists"},
+	{Kind: models.ResultVideo, ID: "vid-zig", Title: "Zig in 100 seconds", ChannelID: "UCzig"},
+	{Kind: models.ResultChannel, ID: "UCgophers", Title: "Gophers channel", ChannelID: "UCgophers"},
+}
+
+// newListModel returns a model showing results, sized so every row fits on one page
+func newListModel(t *testing.T, results []models.SearchResult) Model {
+	t.Helper()
+
+	m := NewModel(&flags.Options{}, api.NewClient())
+	t.Cleanup(m.quit)
+	m.state = stateList
+	m.results = results
+	m.refreshList()
+	m.list.SetSize(120, 40)
+	return m
+}
+
+func press(t *testing.T, m Model, key tea.KeyMsg) Model {
+	t.Helper()
+
+	next, _ := m.Update(key)
+	return next.(Model)
+}
+
+func TestEnterOnFilteredList(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		check  func(t *testing.T, m Model)
+	}{
+		{
+			name:   "channel",
+			filter: "Rust",
+			check: func(t *testing.T, m Model) {
+				if m.state != stateLoading || m.loadingLabel != "Loading channel..." {
+					t.Errorf("state %v %q, want the channel loading", m.state, m.loadingLabel)
+				}
+			},
+		},
+		{
+			name:   "playlist",
+			filter: "playlist",
+			check: func(t *testing.T, m Model) {
+				if m.state != stateLoading || m.loadingLabel != "Loading playlist..." {
+					t.Errorf("state %v %q, want the playlist loading", m.state, m.loadingLabel)
+				}
+			},
+		},
+		{
+			name:   "video",
+			filter: "Zig",
+			check: func(t *testing.T, m Model) {
+				if m.state != stateDetail || m.selectedVideo == nil || m.selectedVideo.ID != "vid-zig" {
+					t.Errorf("state %v with %+v, want the details of vid-zig", m.state, m.selectedVideo)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newListModel(t, mixedResults)
+			// the only match is never the first row of the whole list
+			m.list.SetFilterText(tt.filter)
+			if len(m.list.VisibleItems()) != 1 {
+				t.Fatalf("filter %q matches %d rows", tt.filter, len(m.list.VisibleItems()))
+			}
+
+			m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+			tt.check(t, m)
+		})
+	}
+}
+
+func TestEnterWhileTypingFilter(t *testing.T) {
+	m := newListModel(t, mixedResults)
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	for _, r := range "Zig" {
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.state != stateList {
+		t.Errorf("enter in the filter input opened a result, state %v", m.state)
+	}
+}
+
+func TestOpenChannelFromFilteredList(t *testing.T) {
+	m := newListModel(t, mixedResults)
+	m.list.SetFilterText("Zig")
+
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@")})
+	if m.state != stateLoading || len(m.history) != 1 {
+		t.Fatalf("state %v with %d history entries, want the channel loading", m.state, len(m.history))
+	}
+	if got := m.history[0].index; got != 3 {
+		t.Errorf("history remembers row %d, want 3 (vid-zig in the whole list)", got)
+	}
+
+	// the channel page fails to load and the list comes back, unfiltered, on the same video
+	if !m.goBack() {
+		t.Fatal("nothing to go back to")
+	}
+	if idx, ok := m.selectedIndex(); !ok || m.results[idx].ID != "vid-zig" {
+		t.Errorf("back on row %d, want vid-zig", idx)
+	}
+}
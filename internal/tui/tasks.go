@@ -0,0 +1,33 @@
+package tui
+
+import "context"
+
+// task is one kind of cancellable background work (page loads, downloads, ...). Starting a new run
+// cancels the previous one. The Model keeps tasks behind pointers so every copy of it, including the
+// one Init runs on, shares them.
+type task struct {
+	cancel context.CancelFunc
+	id     int
+}
+
+// start cancels the run in flight and returns the context and id of a new one
+func (t *task) start(parent context.Context) (context.Context, int) {
+	t.stop()
+	ctx, cancel := context.WithCancel(parent)
+	t.cancel = cancel
+	return ctx, t.id
+}
+
+// stop cancels the run in flight, messages still carrying its id are stale from now on
+func (t *task) stop() {
+	if t.cancel != nil {
+		t.cancel()
+		t.cancel = nil
+	}
+	t.id++
+}
+
+// current reports whether id belongs to the latest run
+func (t *task) current(id int) bool {
+	return id == t.id
+}
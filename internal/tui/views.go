@@ -1,22 +1,29 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Drack112/go-youtube/internal/api"
+	"github.com/Drack112/go-youtube/internal/flags"
+	"github.com/Drack112/go-youtube/internal/models"
 	"github.com/Drack112/go-youtube/internal/player"
 	"github.com/Drack112/go-youtube/internal/ui"
+	"github.com/Drack112/go-youtube/pkg/utils"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 func (m *Model) performSearch() tea.Cmd {
+	ctx, loadID := m.load.start(m.ctx)
 	return func() tea.Msg {
-		resp, err := api.SearchVideosWithPagination(m.opts.Input, "")
+		resp, err := m.client.SearchWithOptions(ctx, m.opts.Input, m.searchOpts, "")
 		if err != nil {
-			return searchResultsMsg{err: err}
+			return searchResultsMsg{loadID: loadID, err: err}
 		}
 		return searchResultsMsg{
+			loadID:            loadID,
 			results:           resp.Results,
 			continuationToken: resp.ContinuationToken,
 			hasMore:           resp.HasMore,
@@ -26,13 +33,54 @@ func (m *Model) performSearch() tea.Cmd {
 	}
 }
 
+func (m *Model) fetchPlaylist() tea.Cmd {
+	return m.fetchPlaylistByID(utils.ExtractPlaylistID(m.opts.Input))
+}
+
 func (m *Model) loadMoreResults() tea.Cmd {
+	ctx, loadID := m.load.start(m.ctx)
+
+	if m.playlist != nil {
+		id, token := m.playlist.ID, m.continuationToken
+		return func() tea.Msg {
+			resp, err := m.client.GetPlaylist(ctx, id, token)
+			if err != nil {
+				return searchResultsMsg{loadID: loadID, err: err}
+			}
+			return searchResultsMsg{
+				loadID:            loadID,
+				results:           resp.Results,
+				continuationToken: resp.ContinuationToken,
+				hasMore:           resp.HasMore,
+				isLoadMore:        true,
+			}
+		}
+	}
+
+	if m.channel != nil {
+		id, tab, token := m.channel.ID, m.channelTab, m.continuationToken
+		return func() tea.Msg {
+			resp, err := m.client.GetChannel(ctx, id, tab, token)
+			if err != nil {
+				return searchResultsMsg{loadID: loadID, err: err}
+			}
+			return searchResultsMsg{
+				loadID:            loadID,
+				results:           resp.Results,
+				continuationToken: resp.ContinuationToken,
+				hasMore:           resp.HasMore,
+				isLoadMore:        true,
+			}
+		}
+	}
+
 	return func() tea.Msg {
-		resp, err := api.SearchVideosWithPagination(m.opts.Input, m.continuationToken)
+		resp, err := m.client.SearchWithOptions(ctx, m.opts.Input, m.searchOpts, m.continuationToken)
 		if err != nil {
-			return searchResultsMsg{err: err}
+			return searchResultsMsg{loadID: loadID, err: err}
 		}
 		return searchResultsMsg{
+			loadID:            loadID,
 			results:           resp.Results,
 			continuationToken: resp.ContinuationToken,
 			hasMore:           resp.HasMore,
@@ -42,12 +90,54 @@ func (m *Model) loadMoreResults() tea.Cmd {
 	}
 }
 
+// isSearchPage reports whether the list shows search results, the only page that can be filtered
+func (m Model) isSearchPage() bool {
+	return m.playlist == nil && m.channel == nil && m.opts.InputKind == flags.InputSearchQuery
+}
+
+func (m Model) listTitle() string {
+	more := ""
+	if m.hasMore {
+		more = " - Press 'm' for more"
+	}
+
+	if m.playlist != nil {
+		title := "[≡] " + utils.TruncateText(m.playlist.Title, 40)
+		if m.playlist.OwnerName != "" {
+			title += " by " + m.playlist.OwnerName
+		}
+		return fmt.Sprintf("%s (%d videos, %d loaded%s)", title, m.playlist.VideoCount, len(m.results), more)
+	}
+
+	if m.channel != nil {
+		title := "[@] " + utils.TruncateText(m.channel.Name, 30)
+		if m.channel.Verified {
+			title += " ✓"
+		}
+		if m.channel.SubscriberCount > 0 {
+			title += " • " + utils.FormatCount(m.channel.SubscriberCount) + " subscribers"
+		}
+		return fmt.Sprintf("%s • %s (%d loaded%s - tab for next tab)", title, m.channelTab.Label(), len(m.results), more)
+	}
+
+	title := fmt.Sprintf("[?] Search Results (%d results%s)", len(m.results), more)
+	if filters := activeFilters(m.searchOpts); filters != "" {
+		title += " [" + filters + "]"
+	}
+	return title
+}
+
 func (m Model) loadingView() string {
+	label := "Searching for: " + m.opts.Input
+	if m.loadingLabel != "" {
+		label = m.loadingLabel
+	}
+
 	return lipgloss.Place(
 		m.width, m.height,
 		lipgloss.Center, lipgloss.Center,
 		lipgloss.JoinVertical(
-			lipgloss.Center, m.spinner.View(), "Searching for: "+m.opts.Input, "\n\nPress q to quit",
+			lipgloss.Center, m.spinner.View(), label, "\n\nesc cancel • q quit",
 		),
 	)
 }
@@ -73,11 +163,25 @@ func (m Model) detailView() string {
 	)
 }
 
+func (m Model) errorView() string {
+	err := m.err
+	if err == nil {
+		err = errors.New("unknown error")
+	}
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(
+			lipgloss.Center, ui.CreateErrorBox("Something went wrong", err), "\n\nq quit",
+		),
+	)
+}
+
 func (m Model) createDetailView() string {
 	if m.selectedVideo == nil {
 		return "No video selected"
 	}
-	return ui.CreateDetailedVideoView(*m.selectedVideo)
+	return ui.CreateDetailedVideoView(*m.selectedVideo, m.videoDetails, m.chapterCursor)
 }
 
 func (m Model) createDetailControls() string {
@@ -99,6 +203,32 @@ func (m Model) createDetailControls() string {
 		controlsText = append(controlsText, "[!] No video player found (install mpv)")
 	}
 
+	if m.videoDetails != nil && len(m.videoDetails.Captions) > 0 {
+		controlsText = append(controlsText, "[c]  Captions")
+	}
+
+	if m.videoDetails != nil && len(m.videoDetails.Chapters) > 0 {
+		if chapter := m.selectedChapter(); chapter != nil {
+			controlsText = append(controlsText, fmt.Sprintf("[↕]  [[/]] Chapter: %s (enter plays from %s)", utils.TruncateText(chapter.Title, 20), utils.FormatDuration(chapter.StartSec)))
+		} else {
+			controlsText = append(controlsText, "[↕]  [[/]] Select chapter")
+		}
+	}
+
+	if m.detailsLoading {
+		controlsText = append(controlsText, "[~]  Loading video details...")
+	} else if m.detailsErr != nil && !errors.Is(m.detailsErr, context.Canceled) {
+		title, _ := ui.DescribeError(m.detailsErr)
+		if title == "" {
+			title = "Could not load video details"
+		}
+		controlsText = append(controlsText, "[!]  "+title)
+	}
+
+	if m.selectedVideo != nil && m.selectedVideo.ChannelID != "" {
+		controlsText = append(controlsText, "[@]  Open channel")
+	}
+
 	controlsText = append(controlsText, "[<]  [esc] Back to list")
 	controlsText = append(controlsText, "[x] [q] Quit")
 
@@ -117,28 +247,98 @@ func (m *Model) playVideo() tea.Cmd {
 		}
 
 		playerType := player.PlayerType(m.playerType)
-		err := player.StreamVideo(m.selectedVideo.URL, playerType, m.opts.Quality, m.opts.WindowMode)
+		quality := m.opts.Quality
+		if m.videoDetails != nil {
+			quality = m.resolvedFlagQuality().Quality
+		}
+		startSec := 0
+		if chapter := m.selectedChapter(); chapter != nil {
+			startSec = chapter.StartSec
+		}
+		err := player.StreamVideo(m.ctx, m.selectedVideo.URL, playerType, quality, m.opts.WindowMode, startSec)
 		if err != nil {
 			return tea.Println(fmt.Sprintf("Failed to play video: %v", err))
 		}
 		return nil
 	}
 }
+func (m *Model) playPlaylist() tea.Cmd {
+	return func() tea.Msg {
+		if m.playlist == nil {
+			return nil
+		}
+
+		playerType := player.PlayerType(m.playerType)
+		err := player.StreamVideo(m.ctx, m.playlist.URL, playerType, m.opts.Quality, m.opts.WindowMode, 0)
+		if err != nil {
+			return tea.Println(fmt.Sprintf("Failed to play playlist: %v", err))
+		}
+		return nil
+	}
+}
+
+// playMix streams a mix through its watch URL, mpv keeps the list= parameter and plays the whole radio
+func (m *Model) playMix(url string) tea.Cmd {
+	ctx, playerType, quality, windowMode := m.ctx, player.PlayerType(m.playerType), m.opts.Quality, m.opts.WindowMode
+	return func() tea.Msg {
+		if err := player.StreamVideo(ctx, url, playerType, quality, windowMode, 0); err != nil {
+			return tea.Println(fmt.Sprintf("Failed to play mix: %v", err))
+		}
+		return nil
+	}
+}
+
+func (m *Model) downloadPlaylistCmd() tea.Cmd {
+	ctx, _ := m.download.start(m.ctx)
+	url, quality := m.playlist.URL, m.opts.Quality
+	return func() tea.Msg {
+		err := player.DownloadPlaylist(ctx, url, "", quality)
+		return downloadResultMsg{err: err}
+	}
+}
+
 func (m *Model) startDownloadCmd(url, container, quality string, withThumb bool) tea.Cmd {
+	ctx, _ := m.download.start(m.ctx)
 	return func() tea.Msg {
-		err := player.DownloadVideo(url, container, quality, withThumb)
+		err := player.DownloadVideo(ctx, url, container, quality, withThumb)
 		return downloadResultMsg{err: err}
 	}
 }
 
+// selectedChapter returns the chapter under the cursor, or nil to play from the beginning
+func (m Model) selectedChapter() *models.Chapter {
+	if m.videoDetails == nil || m.chapterCursor < 0 || m.chapterCursor >= len(m.videoDetails.Chapters) {
+		return nil
+	}
+	return &m.videoDetails.Chapters[m.chapterCursor]
+}
+
+// resolvedFlagQuality maps the -quality flag onto a format the selected video really has
+func (m Model) resolvedFlagQuality() api.QualityOption {
+	resolved, _ := api.ResolveQuality(m.downloadQualities, m.opts.Quality)
+	return resolved
+}
+
+func qualityLabel(q api.QualityOption) string {
+	if q.Size > 0 {
+		return fmt.Sprintf("%s (~%s)", q.Label, utils.FormatBytes(q.Size))
+	}
+	return q.Label
+}
+
 func (m Model) renderDownloadModal() string {
 	width := 48
 	lines := []string{}
 
 	if m.opts.QualityProvided {
-		lines = append(lines, "Quality (from flag): "+m.opts.Quality)
+		resolved, exact := api.ResolveQuality(m.downloadQualities, m.opts.Quality)
+		q := qualityLabel(resolved)
+		if !exact {
+			q = fmt.Sprintf("%s (%s not available)", q, m.opts.Quality)
+		}
+		lines = append(lines, "Quality (from flag): "+q)
 	} else {
-		q := m.downloadQualities[m.selectedQuality]
+		q := qualityLabel(m.downloadQualities[m.selectedQuality])
 		if m.downloadCursor == 0 {
 			q = "> " + q
 		}
@@ -164,6 +364,7 @@ func (m Model) renderDownloadModal() string {
 	if m.downloadInProgress {
 		lines = append(lines, "\nDownloading...")
 		lines = append(lines, " "+m.spinner.View())
+		lines = append(lines, "Press Esc to cancel")
 	} else if m.downloadMessage != "" {
 		lines = append(lines, "\n"+m.downloadMessage)
 		lines = append(lines, "Press Enter/Esc to close")
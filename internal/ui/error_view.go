@@ -1,11 +1,49 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"errors"
 
-func CreateErrorBox(title, message string) string {
+	"github.com/Drack112/go-youtube/internal/api"
+	"github.com/Drack112/go-youtube/pkg/httpx"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CreateErrorBox renders err under title, with a hint on what to do when it is one of the api errors
+func CreateErrorBox(title string, err error) string {
 	errorStyle := ContainerStyle.Copy().BorderForeground(Error).Background(lipgloss.Color("#2A1A2E"))
 	titleStyle := TitleStyle.Copy().Foreground(Error)
 
+	message := err.Error()
+	if _, hint := DescribeError(err); hint != "" {
+		message += "\n\n" + hint
+	}
+
 	content := titleStyle.Render("[ERROR] " + title + "\n\n" + NormalTextStyle.Render(message))
 	return errorStyle.Render(content)
 }
+
+// DescribeError returns a short title for err and a hint on how to get past it.
+// Both are empty for errors the api package does not classify.
+func DescribeError(err error) (title, hint string) {
+	switch {
+	case errors.Is(err, api.ErrRateLimited):
+		return "Rate limited", "YouTube is throttling this connection. Wait a few minutes before trying again, or switch networks."
+	case errors.Is(err, api.ErrConsentRequired):
+		return "Consent required", "YouTube redirected to its cookie consent page. Accept it once in a browser on this network and try again."
+	case errors.Is(err, api.ErrAgeRestricted):
+		return "Age restricted", "This video requires a signed-in account to confirm your age. Open it in a browser instead."
+	case errors.Is(err, api.ErrPrivate):
+		return "Private video", "Only accounts the uploader shared it with can watch this video."
+	case errors.Is(err, api.ErrMembersOnly):
+		return "Members only", "This video is available to channel members only."
+	case errors.Is(err, api.ErrRegionBlocked):
+		return "Blocked in your region", "The uploader has not made this video available in your country. A proxy in another region may work."
+	case errors.Is(err, api.ErrVideoUnavailable):
+		return "Video unavailable", "It may have been removed or the link may be wrong."
+	case errors.Is(err, httpx.ErrOffline):
+		return "Not available offline", "This page is not in the cache yet. Open it once while online, or run without -offline."
+	case errors.Is(err, api.ErrLayoutChanged):
+		return "Unexpected response", "YouTube changed its page layout. Update go-youtube, and if it persists, report an issue with the output of -debug."
+	}
+	return "", ""
+}
@@ -39,6 +39,24 @@ func createResultItem(result models.SearchResult, index int) string {
 
 	var metaParts []string
 
+	switch result.Kind {
+	case models.ResultPlaylist:
+		metaParts = append(metaParts, KindBadgeStyle.Render("PLAYLIST"))
+	case models.ResultMix:
+		metaParts = append(metaParts, KindBadgeStyle.Render("MIX"))
+	case models.ResultChannel:
+		metaParts = append(metaParts, KindBadgeStyle.Render("CHANNEL"))
+		if result.Subscribers > 0 {
+			metaParts = append(metaParts, MutedTextStyle.Render(utils.FormatCount(result.Subscribers)+" subscribers"))
+		}
+	case models.ResultMovie:
+		metaParts = append(metaParts, KindBadgeStyle.Render("MOVIE"))
+	}
+
+	if result.VideoCount > 0 {
+		metaParts = append(metaParts, MutedTextStyle.Render(fmt.Sprintf("%d videos", result.VideoCount)))
+	}
+
 	if result.IsLive {
 		metaParts = append(metaParts, LiveBadgeStyle.Render("LIVE"))
 	}
@@ -47,21 +65,61 @@ func createResultItem(result models.SearchResult, index int) string {
 		metaParts = append(metaParts, ShortBadgeStyle.Render("SHORT"))
 	}
 
+	if result.Badges.New {
+		metaParts = append(metaParts, KindBadgeStyle.Render("NEW"))
+	}
+
+	if result.Badges.FourK {
+		metaParts = append(metaParts, KindBadgeStyle.Render("4K"))
+	}
+
+	if result.Badges.HDR {
+		metaParts = append(metaParts, KindBadgeStyle.Render("HDR"))
+	}
+
+	if result.Badges.Captions {
+		metaParts = append(metaParts, KindBadgeStyle.Render("CC"))
+	}
+
 	if result.Duration != "" && !result.IsLive {
 		metaParts = append(metaParts, DurationBadgeStyle.Render(utils.FormatDuration(result.DurationSec)))
 	}
 
 	if result.ChannelName != "" {
-		channelText := ChannelBadgeStyle.Render(utils.TruncateText(result.ChannelName, 20))
+		name := result.ChannelName
+		switch {
+		case result.Artist:
+			name += " ♪"
+		case result.Verified:
+			name += " ✓"
+		}
+		channelText := ChannelBadgeStyle.Render(utils.TruncateText(name, 20))
 		metaParts = append(metaParts, channelText)
 	}
 
+	var stats []string
+	if result.ViewCount > 0 {
+		stats = append(stats, utils.FormatViews(result.ViewCount))
+	}
+	if published := utils.PublishedLabel(result.Published, result.PublishedAge); published != "" {
+		stats = append(stats, published)
+	}
+	if len(stats) > 0 {
+		metaParts = append(metaParts, MutedTextStyle.Render(strings.Join(stats, " • ")))
+	}
+
 	if len(metaParts) > 0 {
 		metaLine := lipgloss.NewStyle().MarginLeft(4).Render(strings.Join(metaParts, " "))
 		item.WriteString(metaLine)
 		item.WriteString("\n")
 	}
 
+	if result.Snippet != "" {
+		snippetStyle := NormalTextStyle.Copy().MarginLeft(4)
+		item.WriteString(snippetStyle.Render(utils.TruncateText(result.Snippet, 63)))
+		item.WriteString("\n")
+	}
+
 	urlStyle := MutedTextStyle.Copy().Italic(true).MarginLeft(4)
 	item.WriteString(urlStyle.Render(utils.TruncateText(result.URL, 63)))
 
@@ -34,6 +34,7 @@ var (
 	ShortBadgeStyle    = lipgloss.NewStyle().Foreground(TextPrimary).Background(AccentBlue).Bold(true).Padding(0, 1).MarginRight(1)
 	DurationBadgeStyle = lipgloss.NewStyle().Foreground(TextPrimary).Background(AccentGreen).Bold(true).Padding(0, 1).MarginRight(1)
 	ChannelBadgeStyle  = lipgloss.NewStyle().Foreground(TextPrimary).Background(SecondaryPurple).Bold(true).Padding(0, 1).MarginRight(1)
+	KindBadgeStyle     = lipgloss.NewStyle().Foreground(TextPrimary).Background(PrimaryPurple).Bold(true).Padding(0, 1).MarginRight(1)
 
 	SectionStyle   = lipgloss.NewStyle().MarginTop(1).PaddingLeft(1).Border(lipgloss.NormalBorder(), false, false, false, true)
 	MetadataStyle  = lipgloss.NewStyle().Foreground(TextMuted).Italic(true)
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -9,7 +10,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-func CreateDetailedVideoView(video models.SearchResult) string {
+// CreateDetailedVideoView renders a video; chapterCursor marks the selected chapter (-1 for none)
+func CreateDetailedVideoView(video models.SearchResult, details *models.Video, chapterCursor int) string {
 	var content strings.Builder
 
 	content.WriteString(createVideoHeader(video))
@@ -21,8 +23,28 @@ func CreateDetailedVideoView(video models.SearchResult) string {
 	content.WriteString(createMetadataSection(video))
 	content.WriteString("\n\n")
 
+	if details != nil {
+		content.WriteString(createStatsSection(details))
+		content.WriteString("\n\n")
+	}
+
 	if video.ChannelName != "" {
-		content.WriteString(createChannelSection(video))
+		content.WriteString(createChannelSection(video, details))
+		content.WriteString("\n\n")
+	}
+
+	if details != nil && len(details.Captions) > 0 {
+		content.WriteString(createCaptionsSection(details))
+		content.WriteString("\n\n")
+	}
+
+	if details != nil && len(details.Chapters) > 0 {
+		content.WriteString(createChaptersSection(details.Chapters, chapterCursor))
+		content.WriteString("\n\n")
+	}
+
+	if details != nil && details.Description != "" {
+		content.WriteString(createDescriptionSection(details))
 		content.WriteString("\n\n")
 	}
 
@@ -114,7 +136,7 @@ func createMetadataSection(video models.SearchResult) string {
 	return SectionStyle.Render(metadata.String())
 }
 
-func createChannelSection(video models.SearchResult) string {
+func createChannelSection(video models.SearchResult, details *models.Video) string {
 	var channel strings.Builder
 
 	channel.WriteString(TitleStyle.Render("[@] Channel"))
@@ -130,9 +152,162 @@ func createChannelSection(video models.SearchResult) string {
 		channel.WriteString("\n")
 	}
 
+	if details != nil {
+		if details.Channel.SubscriberCount > 0 {
+			channel.WriteString(NormalTextStyle.Render("  Subscribers: "))
+			channel.WriteString(SuccessTextStyle.Render(utils.FormatCount(details.Channel.SubscriberCount)))
+			channel.WriteString("\n")
+		}
+
+		if details.Channel.OfficialArtist {
+			channel.WriteString(AccentTextStyle.Render("  [♪] Official Artist"))
+			channel.WriteString("\n")
+		} else if details.Channel.Verified {
+			channel.WriteString(AccentTextStyle.Render("  [✓] Verified"))
+			channel.WriteString("\n")
+		}
+	}
+
 	return SectionStyle.Render(channel.String())
 }
 
+func createStatsSection(details *models.Video) string {
+	var stats strings.Builder
+
+	stats.WriteString(TitleStyle.Render("[#] Statistics"))
+	stats.WriteString("\n")
+
+	writeStat := func(label, value string) {
+		stats.WriteString(NormalTextStyle.Render("  " + label + ": "))
+		stats.WriteString(SuccessTextStyle.Render(value))
+		stats.WriteString("\n")
+	}
+
+	if details.ViewCount > 0 {
+		writeStat("Views", utils.FormatCount(details.ViewCount))
+	}
+	if details.LikeCount > 0 {
+		writeStat("Likes", utils.FormatCount(details.LikeCount))
+	}
+	if details.CommentCount > 0 {
+		writeStat("Comments", utils.FormatCount(details.CommentCount))
+	}
+	if details.PublishedAt != "" {
+		writeStat("Published", details.PublishedAt)
+	}
+	if details.Category != "" {
+		writeStat("Category", details.Category)
+	}
+
+	if music := details.MusicMetadata; music != nil {
+		if music.Song != "" {
+			writeStat("Song", music.Song)
+		}
+		if music.Artist != "" {
+			writeStat("Artist", music.Artist)
+		}
+		if music.Album != "" {
+			writeStat("Album", music.Album)
+		}
+	}
+
+	if len(details.Keywords) > 0 {
+		keywords := utils.TruncateText(strings.Join(details.Keywords, ", "), 60)
+		stats.WriteString(NormalTextStyle.Render("  Keywords: "))
+		stats.WriteString(MutedTextStyle.Render(keywords))
+		stats.WriteString("\n")
+	}
+
+	return SectionStyle.Render(stats.String())
+}
+
+func createCaptionsSection(details *models.Video) string {
+	var manual, auto []string
+	for _, t := range details.Captions {
+		if t.AutoGenerated {
+			auto = append(auto, t.LanguageCode)
+		} else {
+			manual = append(manual, t.LanguageCode)
+		}
+	}
+
+	var captions strings.Builder
+	captions.WriteString(TitleStyle.Render("[cc] Captions"))
+	captions.WriteString("\n")
+
+	if len(manual) > 0 {
+		captions.WriteString(NormalTextStyle.Render("  Manual: "))
+		captions.WriteString(AccentTextStyle.Render(utils.TruncateText(strings.Join(manual, ", "), 56)))
+		captions.WriteString("\n")
+	}
+	if len(auto) > 0 {
+		captions.WriteString(NormalTextStyle.Render("  Auto-generated: "))
+		captions.WriteString(MutedTextStyle.Render(utils.TruncateText(strings.Join(auto, ", "), 48)))
+		captions.WriteString("\n")
+	}
+
+	return SectionStyle.Render(captions.String())
+}
+
+func createChaptersSection(chapters []models.Chapter, cursor int) string {
+	const visible = 8
+
+	// keep the cursor inside a small scrolling window
+	start := 0
+	if cursor >= visible {
+		start = cursor - visible + 1
+	}
+	end := min(start+visible, len(chapters))
+
+	var section strings.Builder
+	section.WriteString(TitleStyle.Render("[≡] Chapters"))
+	section.WriteString("\n")
+
+	if start > 0 {
+		section.WriteString(MutedTextStyle.Render("  ..."))
+		section.WriteString("\n")
+	}
+
+	for i := start; i < end; i++ {
+		c := chapters[i]
+		line := utils.FormatDuration(c.StartSec)
+		if c.StartSec == 0 {
+			line = "00:00"
+		}
+		line += "  " + utils.TruncateText(c.Title, 54)
+
+		if i == cursor {
+			section.WriteString(AccentTextStyle.Bold(true).Render("> " + line))
+		} else {
+			section.WriteString(NormalTextStyle.Render("  " + line))
+		}
+		section.WriteString("\n")
+	}
+
+	if end < len(chapters) {
+		section.WriteString(MutedTextStyle.Render(fmt.Sprintf("  ... %d more", len(chapters)-end)))
+		section.WriteString("\n")
+	}
+
+	return SectionStyle.Render(section.String())
+}
+
+func createDescriptionSection(details *models.Video) string {
+	lines := strings.Split(strings.TrimSpace(details.Description), "\n")
+	if len(lines) > 6 {
+		lines = append(lines[:6], "...")
+	}
+
+	for i, line := range lines {
+		lines[i] = "  " + utils.TruncateText(line, 66)
+	}
+
+	return SectionStyle.Render(
+		TitleStyle.Render("[=] Description") + "\n" +
+			MetadataStyle.Render(strings.Join(lines, "\n")),
+	)
+}
+
 func createURLSection(video models.SearchResult) string {
 	urlStyle := lipgloss.NewStyle().
 		Foreground(AccentBlue).
@@ -0,0 +1,331 @@
+package httpx
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Drack112/go-youtube/pkg/logger"
+)
+
+// ErrOffline is returned in offline mode for requests the cache cannot answer
+var ErrOffline = errors.New("not available offline")
+
+// Kind groups requests that share a cache TTL
+type Kind string
+
+const (
+	KindSearch    Kind = "search"
+	KindVideo     Kind = "video"
+	KindBrowse    Kind = "browse" // channels and playlists
+	KindFeed      Kind = "feed"   // channel RSS feeds
+	KindCaptions  Kind = "captions"
+	KindThumbnail Kind = "thumbnail"
+)
+
+// CacheOptions configures the on-disk cache
+type CacheOptions struct {
+	Dir     string
+	MaxSize int64                  // bytes kept on disk, least recently used entries go first
+	TTL     map[Kind]time.Duration // kinds missing here are not cached
+	Offline bool                   // answer only from the cache, expired entries included
+}
+
+// DefaultTTL keeps search results and feeds briefly, since new uploads show up there first
+var DefaultTTL = map[Kind]time.Duration{
+	KindSearch:    15 * time.Minute,
+	KindFeed:      15 * time.Minute,
+	KindBrowse:    time.Hour,
+	KindVideo:     24 * time.Hour,
+	KindCaptions:  7 * 24 * time.Hour,
+	KindThumbnail: 30 * 24 * time.Hour,
+}
+
+// DefaultCacheSize caps the cache at 256 MiB
+const DefaultCacheSize = 256 << 20
+
+// DefaultCacheDir returns $XDG_CACHE_HOME/go-youtube, or the platform equivalent
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-youtube"), nil
+}
+
+// storedHeader records when an entry was written; file modification times track use for eviction
+const storedHeader = "X-Go-Youtube-Stored"
+
+// Cache is an http.RoundTripper that keeps successful responses on disk, one file per request.
+// Fresh entries are served without touching the network. When the network fails or YouTube answers
+// 429 or 5xx, an expired entry is better than nothing and is served too.
+type Cache struct {
+	Base http.RoundTripper // nil means http.DefaultTransport
+	opts CacheOptions
+	mu   sync.Mutex // serializes writes and eviction
+}
+
+func NewCache(base http.RoundTripper, opts CacheOptions) *Cache {
+	return &Cache{Base: base, opts: opts}
+}
+
+func (c *Cache) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := c.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ttl, cacheable := c.opts.TTL[requestKind(req)]
+	if !cacheable || (req.Method != http.MethodGet && req.Method != http.MethodPost) {
+		if c.opts.Offline {
+			return nil, ErrOffline
+		}
+		return base.RoundTrip(req)
+	}
+
+	req, path, err := c.entryPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, stored := c.load(req, path)
+	// Cache-Control: no-cache asks for a fresh copy, an old one is still the fallback
+	fresh := !strings.Contains(req.Header.Get("Cache-Control"), "no-cache") && time.Since(stored) < ttl
+	if cached != nil && (c.opts.Offline || fresh) {
+		logger.Debug("[Cache] hit", "url", req.URL.Redacted(), "age", time.Since(stored).Round(time.Second))
+		return cached, nil
+	}
+	if c.opts.Offline {
+		return nil, ErrOffline
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		if cached != nil && req.Context().Err() == nil {
+			logger.Debug("[Cache] request failed, serving expired entry", "url", req.URL.Redacted(), "error", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	if cached != nil {
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			logger.Debug("[Cache] request failed, serving expired entry", "url", req.URL.Redacted(), "status", resp.StatusCode)
+			resp.Body.Close()
+			return cached, nil
+		}
+		cached.Body.Close()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := c.store(path, resp, body); err != nil {
+		logger.Warn("[Cache] store failed", "error", err)
+	}
+	return resp, nil
+}
+
+// entryPath names the file of req after its method, URL and body. Reading the body means handing a copy
+// of req with a fresh one to the next transport.
+func (c *Cache) entryPath(req *http.Request) (*http.Request, string, error) {
+	h := sha256.New()
+	io.WriteString(h, req.Method+" "+req.URL.String()+"\n")
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, "", err
+		}
+		h.Write(body)
+
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	return req, filepath.Join(c.opts.Dir, hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// load reads the entry at path, marking it as recently used
+func (c *Cache) load(req *http.Request, path string) (*http.Response, time.Time) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		os.Remove(path)
+		return nil, time.Time{}
+	}
+	stored, err := http.ParseTime(resp.Header.Get(storedHeader))
+	if err != nil {
+		resp.Body.Close()
+		os.Remove(path)
+		return nil, time.Time{}
+	}
+	resp.Header.Del(storedHeader)
+
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return resp, stored
+}
+
+// store writes resp with body to path, then trims the cache back under MaxSize
+func (c *Cache) store(path string, resp *http.Response, body []byte) error {
+	entry := &http.Response{
+		StatusCode:    resp.StatusCode,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		ContentLength: int64(len(body)),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		entry.Header.Set("Content-Type", ct)
+	}
+	entry.Header.Set(storedHeader, time.Now().UTC().Format(http.TimeFormat))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(c.opts.Dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.opts.Dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = entry.Write(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	return c.evict()
+}
+
+// evict removes the least recently used entries until the cache fits in MaxSize
+func (c *Cache) evict() error {
+	if c.opts.MaxSize <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(c.opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	var files []fs.FileInfo
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+
+	slices.SortFunc(files, func(a, b fs.FileInfo) int {
+		return a.ModTime().Compare(b.ModTime())
+	})
+	for _, f := range files {
+		if total <= c.opts.MaxSize {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.opts.Dir, f.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		total -= f.Size()
+		logger.Debug("[Cache] evicted", "entry", f.Name())
+	}
+	return nil
+}
+
+// requestKind tells what a request is for, from its path: YouTube's own pages and youtubei calls, and the
+// REST APIs of the Invidious (/api/v1/...) and Piped instances used instead of it. Requests of no known
+// kind are not cached.
+func requestKind(req *http.Request) Kind {
+	host, path := req.URL.Hostname(), req.URL.Path
+
+	switch {
+	case strings.HasSuffix(host, "ytimg.com") || strings.HasSuffix(host, "ggpht.com"):
+		return KindThumbnail
+	case strings.HasPrefix(path, "/api/v1/"):
+		return invidiousKind(strings.TrimPrefix(path, "/api/v1/"))
+	case path == "/results" || path == "/youtubei/v1/search":
+		return KindSearch
+	case path == "/watch" || path == "/youtubei/v1/player" || path == "/youtubei/v1/next":
+		return KindVideo
+	case path == "/feeds/videos.xml":
+		return KindFeed
+	case path == "/api/timedtext":
+		return KindCaptions
+	case path == "/playlist" || path == "/youtubei/v1/browse" || path == "/youtubei/v1/navigation/resolve_url" ||
+		strings.HasPrefix(path, "/@") || strings.HasPrefix(path, "/channel/") || strings.HasPrefix(path, "/c/"):
+		return KindBrowse
+	}
+	return pipedKind(path)
+}
+
+func invidiousKind(endpoint string) Kind {
+	name, _, _ := strings.Cut(endpoint, "/")
+	switch name {
+	case "search":
+		return KindSearch
+	case "videos", "comments":
+		return KindVideo
+	case "channels", "playlists", "resolveurl":
+		return KindBrowse
+	case "captions":
+		return KindCaptions
+	}
+	return ""
+}
+
+// pipedKind covers the Piped endpoints and the /nextpage/ variants they are paged through
+func pipedKind(path string) Kind {
+	path = strings.TrimPrefix(path, "/nextpage")
+	name, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	switch name {
+	case "search":
+		return KindSearch
+	case "streams", "comments":
+		return KindVideo
+	case "channel", "channels", "playlists", "user":
+		return KindBrowse
+	}
+	return ""
+}
@@ -0,0 +1,234 @@
+package httpx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counting echoes the request URI and body, counting the requests that reach it
+func counting(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte(r.URL.RequestURI() + string(body)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func get(t *testing.T, client *http.Client, url string) (string, error) {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+func TestCacheServesFreshEntries(t *testing.T) {
+	srv, calls := counting(t)
+	client := &http.Client{Transport: NewCache(nil, CacheOptions{Dir: t.TempDir(), TTL: DefaultTTL})}
+
+	for range 3 {
+		body, err := get(t, client, srv.URL+"/results?search_query=go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body != "/results?search_query=go" {
+			t.Fatalf("got %q", body)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("%d requests reached the server, want 1", calls.Load())
+	}
+
+	// a different query is a different entry, and unknown paths are not cached
+	get(t, client, srv.URL+"/results?search_query=rust")
+	get(t, client, srv.URL+"/other")
+	get(t, client, srv.URL+"/other")
+	if calls.Load() != 4 {
+		t.Fatalf("%d requests reached the server, want 4", calls.Load())
+	}
+}
+
+func TestCacheKeysPostBody(t *testing.T) {
+	srv, calls := counting(t)
+	client := &http.Client{Transport: NewCache(nil, CacheOptions{Dir: t.TempDir(), TTL: DefaultTTL})}
+
+	for _, payload := range []string{`{"a":1}`, `{"a":2}`, `{"a":1}`} {
+		resp, err := client.Post(srv.URL+"/youtubei/v1/browse", "application/json", bytes.NewReader([]byte(payload)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if want := "/youtubei/v1/browse" + payload; string(body) != want {
+			t.Fatalf("got %q, want %q", body, want)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("%d requests reached the server, want 2", calls.Load())
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	srv, calls := counting(t)
+	dir := t.TempDir()
+	ttl := map[Kind]time.Duration{KindVideo: time.Hour}
+	client := &http.Client{Transport: NewCache(nil, CacheOptions{Dir: dir, TTL: ttl})}
+
+	get(t, client, srv.URL+"/watch?v=x")
+	ttl[KindVideo] = 0
+	get(t, client, srv.URL+"/watch?v=x")
+	if calls.Load() != 2 {
+		t.Fatalf("expired entry was served, %d requests", calls.Load())
+	}
+
+	// once the server is gone the expired entry is better than an error
+	srv.Close()
+	body, err := get(t, client, srv.URL+"/watch?v=x")
+	if err != nil || body != "/watch?v=x" {
+		t.Fatalf("got %q, %v", body, err)
+	}
+}
+
+func TestCacheOffline(t *testing.T) {
+	srv, calls := counting(t)
+	dir := t.TempDir()
+
+	online := &http.Client{Transport: NewCache(nil, CacheOptions{Dir: dir, TTL: DefaultTTL})}
+	get(t, online, srv.URL+"/watch?v=x")
+
+	offline := &http.Client{Transport: NewCache(nil, CacheOptions{Dir: dir, TTL: map[Kind]time.Duration{KindVideo: 0}, Offline: true})}
+	if body, err := get(t, offline, srv.URL+"/watch?v=x"); err != nil || body != "/watch?v=x" {
+		t.Fatalf("got %q, %v", body, err)
+	}
+	if _, err := get(t, offline, srv.URL+"/watch?v=y"); !errors.Is(err, ErrOffline) {
+		t.Fatalf("want ErrOffline, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("offline mode reached the server, %d requests", calls.Load())
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	srv, _ := counting(t)
+	dir := t.TempDir()
+	client := &http.Client{Transport: NewCache(nil, CacheOptions{Dir: dir, TTL: DefaultTTL, MaxSize: 1})}
+
+	get(t, client, srv.URL+"/watch?v=a")
+	get(t, client, srv.URL+"/watch?v=b")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("%d entries left over a 1 byte cap", len(entries))
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	srv, calls := counting(t)
+	dir := t.TempDir()
+	cache := NewCache(nil, CacheOptions{Dir: dir, TTL: DefaultTTL})
+	client := &http.Client{Transport: cache}
+
+	get(t, client, srv.URL+"/watch?v=a")
+	get(t, client, srv.URL+"/watch?v=b")
+	entries, _ := os.ReadDir(dir)
+	info, _ := entries[0].Info()
+
+	// age both entries, then use a again so b is the least recently used
+	old := time.Now().Add(-time.Hour)
+	for _, e := range entries {
+		os.Chtimes(dir+"/"+e.Name(), old, old)
+	}
+	get(t, client, srv.URL+"/watch?v=a")
+
+	// room for two entries
+	cache.opts.MaxSize = 2*info.Size() + 1
+	get(t, client, srv.URL+"/watch?v=c")
+
+	before := calls.Load()
+	get(t, client, srv.URL+"/watch?v=a")
+	if calls.Load() != before {
+		t.Fatal("a was evicted although it was used recently")
+	}
+	get(t, client, srv.URL+"/watch?v=b")
+	if calls.Load() == before {
+		t.Fatal("b survived eviction")
+	}
+}
+
+func TestRequestKind(t *testing.T) {
+	tests := []struct {
+		url  string
+		want Kind
+	}{
+		{"https://www.youtube.com/results?search_query=go", KindSearch},
+		{"https://www.youtube.com/youtubei/v1/player", KindVideo},
+		{"https://www.youtube.com/@GoogleDevelopers/videos", KindBrowse},
+		{"https://i.ytimg.com/vi/x/hqdefault.jpg", KindThumbnail},
+
+		{"https://inv.example/api/v1/search?q=go&page=2", KindSearch},
+		{"https://inv.example/api/v1/videos/f6kdp27TYZs", KindVideo},
+		{"https://inv.example/api/v1/comments/f6kdp27TYZs", KindVideo},
+		{"https://inv.example/api/v1/channels/UCx/videos", KindBrowse},
+		{"https://inv.example/api/v1/playlists/PLx?page=1", KindBrowse},
+		{"https://inv.example/api/v1/resolveurl?url=x", KindBrowse},
+		{"https://inv.example/api/v1/captions/f6kdp27TYZs?label=English", KindCaptions},
+		{"https://inv.example/api/v1/stats", ""},
+
+		{"https://pipedapi.example/search?q=go&filter=all", KindSearch},
+		{"https://pipedapi.example/nextpage/search?q=go&nextpage=x", KindSearch},
+		{"https://pipedapi.example/streams/f6kdp27TYZs", KindVideo},
+		{"https://pipedapi.example/nextpage/comments/f6kdp27TYZs?nextpage=x", KindVideo},
+		{"https://pipedapi.example/c/@GoogleDevelopers", KindBrowse},
+		{"https://pipedapi.example/channels/tabs?data=x", KindBrowse},
+		{"https://pipedapi.example/nextpage/channel/UCx?nextpage=x", KindBrowse},
+		{"https://pipedapi.example/playlists/PLx", KindBrowse},
+		{"https://pipedproxy.example/api/timedtext?v=x&lang=en", KindCaptions},
+		{"https://pipedapi.example/healthcheck", ""},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+		if got := requestKind(req); got != tt.want {
+			t.Errorf("requestKind(%s) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestCacheOfflineAlternativeBackends(t *testing.T) {
+	srv, calls := counting(t)
+	dir := t.TempDir()
+	paths := []string{"/api/v1/videos/x", "/api/v1/search?q=go", "/streams/x", "/nextpage/playlists/PLx?nextpage=y"}
+
+	online := &http.Client{Transport: NewCache(nil, CacheOptions{Dir: dir, TTL: DefaultTTL})}
+	for _, p := range paths {
+		get(t, online, srv.URL+p)
+	}
+
+	offline := &http.Client{Transport: NewCache(nil, CacheOptions{Dir: dir, TTL: DefaultTTL, Offline: true})}
+	for _, p := range paths {
+		if body, err := get(t, offline, srv.URL+p); err != nil || body != p {
+			t.Errorf("%s offline: got %q, %v", p, body, err)
+		}
+	}
+	if calls.Load() != int32(len(paths)) {
+		t.Fatalf("offline mode reached the server, %d requests", calls.Load())
+	}
+}
@@ -0,0 +1,98 @@
+package httpx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadCookieFile reads a Netscape cookies.txt, the format browser extensions and yt-dlp export, into a new jar
+func LoadCookieFile(path string) (*cookiejar.Jar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cookies, err := parseCookieFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, fc := range cookies {
+		scheme := "http"
+		if fc.cookie.Secure {
+			scheme = "https"
+		}
+		u := &url.URL{Scheme: scheme, Host: strings.TrimPrefix(fc.domain, "."), Path: fc.cookie.Path}
+		jar.SetCookies(u, []*http.Cookie{fc.cookie})
+	}
+	return jar, nil
+}
+
+// fileCookie is one line of a cookies.txt. Cookies for a whole domain carry it in cookie.Domain,
+// host-only ones leave it empty, as in a Set-Cookie without Domain.
+type fileCookie struct {
+	domain string
+	cookie *http.Cookie
+}
+
+// parseCookieFile reads the tab separated lines of a cookies.txt:
+//
+//	domain  include-subdomains  path  secure  expires  name  value
+//
+// Lines starting with # are comments, except for the #HttpOnly_ prefix curl puts before the domain.
+func parseCookieFile(r io.Reader) ([]fileCookie, error) {
+	var cookies []fileCookie
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := false
+		if rest, ok := strings.CutPrefix(text, "#HttpOnly_"); ok {
+			text, httpOnly = rest, true
+		}
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("line %d: want 7 tab separated fields, got %d", line, len(fields))
+		}
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: bad expiry %q", line, fields[4])
+		}
+
+		c := &http.Cookie{
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+		// session cookies are saved with expiry 0
+		if expires > 0 {
+			c.Expires = time.Unix(expires, 0)
+		}
+		if strings.EqualFold(fields[1], "TRUE") {
+			c.Domain = fields[0]
+		}
+		cookies = append(cookies, fileCookie{domain: fields[0], cookie: c})
+	}
+
+	return cookies, scanner.Err()
+}
@@ -0,0 +1,64 @@
+package httpx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket refilled at rate tokens per second, holding up to burst of them.
+// Callers reserve a token and sleep until it is due, so waiting requests are served in order.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	paused time.Time // nothing is sent before this, set after a 429
+}
+
+func newLimiter(rate float64, burst int) *limiter {
+	b := float64(max(burst, 1))
+	return &limiter{rate: rate, burst: b, tokens: b, last: time.Now()}
+}
+
+// wait blocks until the caller may send a request or ctx is done
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+
+	var delay time.Duration
+	if l.rate > 0 {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+		l.tokens--
+		if l.tokens < 0 {
+			delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+		}
+	}
+	if l.paused.After(now) {
+		delay = max(delay, l.paused.Sub(now))
+	}
+	l.mu.Unlock()
+
+	if err := sleep(ctx, delay); err != nil {
+		// hand the reservation back to the requests still waiting
+		if l.rate > 0 {
+			l.mu.Lock()
+			l.tokens++
+			l.mu.Unlock()
+		}
+		return err
+	}
+	return nil
+}
+
+// pause holds every request back for d
+func (l *limiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.paused) {
+		l.paused = until
+	}
+}
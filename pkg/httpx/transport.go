@@ -0,0 +1,255 @@
+// Package httpx holds the HTTP plumbing shared by every request to YouTube: rate limiting, retries,
+// caching, proxies and cookies.
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Drack112/go-youtube/pkg/logger"
+)
+
+// Options configures a Transport
+type Options struct {
+	RequestsPerSecond float64       // average request rate, 0 removes the limit
+	Burst             int           // requests sent back to back before the rate applies
+	MaxRetries        int           // retries of a transient failure, 0 disables them
+	BaseDelay         time.Duration // first backoff, doubled on every retry
+	MaxDelay          time.Duration // longest backoff, and longest Retry-After worth waiting for
+	Cache             *CacheOptions // nil disables the on-disk cache
+	Proxy             *url.URL      // http, https or socks5 proxy; nil falls back to HTTPS_PROXY and friends
+	Jar               http.CookieJar
+}
+
+// DefaultOptions keeps well below the rate at which YouTube starts answering 429
+var DefaultOptions = Options{
+	RequestsPerSecond: 4,
+	Burst:             8,
+	MaxRetries:        3,
+	BaseDelay:         500 * time.Millisecond,
+	MaxDelay:          30 * time.Second,
+}
+
+// Transport is an http.RoundTripper that spaces requests out with a token bucket and retries
+// idempotent requests that failed for a transient reason: network errors, 429 and 5xx gateway errors.
+// A 429 pauses every request going through the transport, not only the one that got it.
+//
+// GET and HEAD are idempotent. Other methods are retried only when the request carries an
+// Idempotency-Key header, see MarkIdempotent.
+type Transport struct {
+	Base    http.RoundTripper // nil means http.DefaultTransport
+	opts    Options
+	limiter *limiter
+}
+
+func NewTransport(base http.RoundTripper, opts Options) *Transport {
+	return &Transport{
+		Base:    base,
+		opts:    opts,
+		limiter: newLimiter(opts.RequestsPerSecond, opts.Burst),
+	}
+}
+
+// NewClient returns an http.Client sending its requests through a new Transport, behind a Cache
+// when opts.Cache is set
+func NewClient(opts Options) *http.Client {
+	var base http.RoundTripper
+	if opts.Proxy != nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = http.ProxyURL(opts.Proxy)
+		base = t
+	}
+
+	var rt http.RoundTripper = NewTransport(base, opts)
+	if opts.Cache != nil {
+		rt = NewCache(rt, *opts.Cache)
+	}
+	return &http.Client{Transport: rt, Jar: opts.Jar}
+}
+
+// ParseProxy checks a proxy URL such as socks5://127.0.0.1:1080. A bare host:port is taken as an HTTP proxy.
+func ParseProxy(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy %q has no host", raw)
+	}
+	return u, nil
+}
+
+// MarkIdempotent lets Transport retry req although its method is not idempotent, as for read-only POSTs.
+// The nil header value is the net/http convention for this, the header itself is never sent.
+func MarkIdempotent(req *http.Request) {
+	req.Header["Idempotency-Key"] = nil
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			retry, err := rewind(req)
+			if err != nil {
+				return nil, err
+			}
+			req = retry
+		}
+
+		// base closes the body of every request it gets, one that never reaches it is closed here
+		if err := t.limiter.wait(ctx); err != nil {
+			closeBody(req)
+			return nil, err
+		}
+
+		resp, err := base.RoundTrip(req)
+		if attempt >= t.opts.MaxRetries || !retryable(req) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay, ok := t.retryDelay(attempt, resp, err)
+		if !ok {
+			return resp, err
+		}
+
+		if resp != nil {
+			if resp.StatusCode == http.StatusTooManyRequests {
+				t.limiter.pause(delay)
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		logger.Debug("[Transport] retrying", "url", req.URL.Redacted(), "attempt", attempt+1, "delay", delay, "status", status(resp), "error", err)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay decides whether the outcome of an attempt is worth retrying and how long to wait first
+func (t *Transport) retryDelay(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if err != nil {
+		return t.backoff(attempt), true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return 0, false
+	}
+
+	if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+		// a server asking for a long break gets it, the caller sees the error instead
+		if t.opts.MaxDelay > 0 && after > t.opts.MaxDelay {
+			return 0, false
+		}
+		return after, true
+	}
+	return t.backoff(attempt), true
+}
+
+// backoff doubles BaseDelay on every attempt up to MaxDelay and picks a random delay in its upper half,
+// so clients throttled together do not come back together
+func (t *Transport) backoff(attempt int) time.Duration {
+	d := t.opts.BaseDelay << attempt
+	if d <= 0 || (t.opts.MaxDelay > 0 && d > t.opts.MaxDelay) {
+		d = t.opts.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses both forms of the header: delay in seconds and HTTP date
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+func retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		_, key := req.Header["Idempotency-Key"]
+		_, xkey := req.Header["X-Idempotency-Key"]
+		if !key && !xkey {
+			return false
+		}
+	}
+	// a body that cannot be read again cannot be sent again
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns a copy of req with a fresh body for the next attempt
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	return retry, nil
+}
+
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+func status(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
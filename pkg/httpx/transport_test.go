@@ -0,0 +1,195 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetries = Options{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+// flaky answers status to the first failures requests and 200 afterwards
+func flaky(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if calls.Add(1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestTransportRetriesTransientStatus(t *testing.T) {
+	srv, calls := flaky(t, 2, http.StatusServiceUnavailable, nil)
+
+	resp, err := NewClient(fastRetries).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("got status %d after %d calls, want 200 after 3", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestTransportGivesUp(t *testing.T) {
+	srv, calls := flaky(t, 100, http.StatusBadGateway, nil)
+
+	resp, err := NewClient(fastRetries).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway || calls.Load() != 4 {
+		t.Fatalf("got status %d after %d calls, want 502 after 4", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestTransportSkipsPermanentStatus(t *testing.T) {
+	srv, calls := flaky(t, 1, http.StatusNotFound, nil)
+
+	resp, err := NewClient(fastRetries).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if calls.Load() != 1 {
+		t.Fatalf("404 was retried, %d calls", calls.Load())
+	}
+}
+
+func TestTransportPostNeedsIdempotencyKey(t *testing.T) {
+	srv, calls := flaky(t, 1, http.StatusServiceUnavailable, nil)
+	client := NewClient(fastRetries)
+
+	resp, err := client.Post(srv.URL, "application/json", bytes.NewReader([]byte(`{}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Fatalf("plain POST was retried, %d calls", calls.Load())
+	}
+
+	calls.Store(0)
+	req, _ := http.NewRequest("POST", srv.URL, bytes.NewReader([]byte(`{"q":1}`)))
+	MarkIdempotent(req)
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if calls.Load() != 2 || string(body) != `{"q":1}` {
+		t.Fatalf("got %q after %d calls, want the body echoed after 2", body, calls.Load())
+	}
+}
+
+func TestTransportRetryAfter(t *testing.T) {
+	srv, calls := flaky(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+
+	opts := fastRetries
+	opts.MaxDelay = 2 * time.Second
+	start := time.Now()
+	resp, err := NewClient(opts).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("retried after %v, Retry-After asked for 1s", elapsed)
+	}
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Fatalf("got status %d after %d calls", resp.StatusCode, calls.Load())
+	}
+
+	// a Retry-After longer than MaxDelay is handed to the caller
+	srv, calls = flaky(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"3600"}})
+	resp, err = NewClient(fastRetries).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || calls.Load() != 1 {
+		t.Fatalf("got status %d after %d calls, want 429 at once", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestTransportCancelDuringBackoff(t *testing.T) {
+	srv, _ := flaky(t, 100, http.StatusServiceUnavailable, nil)
+
+	opts := fastRetries
+	opts.BaseDelay, opts.MaxDelay = time.Hour, time.Hour
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL, nil)
+	_, err := NewClient(opts).Do(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want DeadlineExceeded, got %v", err)
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed atomic.Bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed.Store(true)
+	return nil
+}
+
+func TestTransportClosesBodyWhenCanceled(t *testing.T) {
+	srv, calls := flaky(t, 0, http.StatusOK, nil)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	body := &closeRecorder{Reader: bytes.NewReader([]byte("payload"))}
+	req, _ := http.NewRequestWithContext(ctx, "POST", srv.URL, body)
+	if _, err := NewTransport(nil, fastRetries).RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want Canceled, got %v", err)
+	}
+	if !body.closed.Load() {
+		t.Error("request body left open")
+	}
+	if calls.Load() != 0 {
+		t.Errorf("server got %d requests", calls.Load())
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(100, 2)
+
+	start := time.Now()
+	for range 6 {
+		if err := l.wait(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 2 from the burst, 4 more at 10ms each
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Fatalf("6 requests at 100/s with burst 2 took only %v", elapsed)
+	}
+}
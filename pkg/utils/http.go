@@ -1,24 +1,94 @@
 package utils
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+
+	"github.com/Drack112/go-youtube/pkg/httpx"
 )
 
-func Fetch(url string) (string, error) {
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36 OPR/123.0.0.0 (Edition Yx 08)")
+// DefaultUserAgent is sent with every request unless the caller sets its own
+const DefaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36 OPR/123.0.0.0 (Edition Yx 08)"
+
+// DefaultClient sends the requests of Fetch and PostJSON. Replace it to route them through a proxy or cookie jar.
+var DefaultClient = httpx.NewClient(httpx.DefaultOptions)
 
-	resp, err := http.DefaultClient.Do(req)
+// DefaultHeaders are added to the requests of Fetch and PostJSON, a User-Agent here replaces DefaultUserAgent
+var DefaultHeaders = http.Header{}
+
+// Fetch GETs url and returns the body; the request is abandoned when ctx is done
+func Fetch(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", err
 	}
 
-	body, _ := io.ReadAll(resp.Body)
+	body, err := Do(DefaultClient, withDefaultHeaders(req))
+	return string(body), err
+}
+
+// PostJSON sends payload as a JSON body and returns the raw response body
+func PostJSON(ctx context.Context, url string, payload any) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return Do(DefaultClient, withDefaultHeaders(req))
+}
+
+func withDefaultHeaders(req *http.Request) *http.Request {
+	for key, values := range DefaultHeaders {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	return req
+}
+
+// Do sends req through client, filling in the default user agent, and returns the response body.
+// Error statuses come back as *HTTPError along with the body.
+func Do(client *http.Client, req *http.Request) ([]byte, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", DefaultUserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
 
-	return string(body), nil
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		return body, &HTTPError{StatusCode: resp.StatusCode, Method: req.Method, URL: req.URL.String()}
+	}
+	return body, nil
+}
+
+// HTTPError reports a response with a 4xx or 5xx status
+type HTTPError struct {
+	StatusCode int
+	Method     string
+	URL        string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
 }
 
 func URLEncode(s string) string {
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"strings"
+	"time"
+)
+
+// The words below cover the languages YouTube text is parsed in: English, Portuguese, Spanish and German.
+// Parsing accepts all of them whatever -lang is, since a single response can mix languages.
+
+// relativeUnits are the unit words of "3 years ago", "há 3 anos", "hace 3 años" and "vor 3 Jahren",
+// matched as prefixes. The first name is the one FormatRelativeTime writes.
+var relativeUnits = []struct {
+	names []string
+	size  time.Duration
+}{
+	{[]string{"year", "ano", "año", "jahr"}, year},
+	{[]string{"month", "mês", "mes", "monat"}, month},
+	{[]string{"week", "semana", "woche"}, week},
+	{[]string{"day", "dia", "día", "tag"}, day},
+	{[]string{"hour", "hora", "stunde"}, time.Hour},
+	{[]string{"minute", "minuto"}, time.Minute},
+	{[]string{"second", "segundo", "sekunde"}, time.Second},
+}
+
+// countSuffixes are the abbreviations of "1.2K", "1,2 mil", "3,4 mi", "3,4 M" and "1,2 Mio."
+var countSuffixes = map[string]float64{
+	"k":     1e3, // en
+	"m":     1e6, // en, es
+	"b":     1e9, // en
+	"mil":   1e3, // pt, es
+	"mi":    1e6, // pt
+	"bi":    1e9, // pt
+	"mil m": 1e9, // es
+	"tsd":   1e3, // de
+	"mio":   1e6, // de
+	"mrd":   1e9, // de
+}
+
+var (
+	subscriberWords = []string{"subscriber", "inscrito", "suscriptor", "abonnent"}
+	viewWords       = []string{"view", "visualiza", "aufruf"}
+	videoWords      = []string{"video", "vídeo"}
+)
+
+// IsSubscriberCount reports whether text reads like "1.2M subscribers" in one of the parsed languages
+func IsSubscriberCount(text string) bool {
+	return containsAny(text, subscriberWords)
+}
+
+// IsViewCount reports whether text reads like "1.2M views" in one of the parsed languages
+func IsViewCount(text string) bool {
+	return containsAny(text, viewWords)
+}
+
+// IsVideoCount reports whether text reads like "42 videos" in one of the parsed languages
+func IsVideoCount(text string) bool {
+	return containsAny(text, videoWords)
+}
+
+func containsAny(text string, words []string) bool {
+	text = strings.ToLower(text)
+	for _, w := range words {
+		if strings.Contains(text, w) {
+			return true
+		}
+	}
+	return false
+}
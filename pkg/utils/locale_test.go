@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		text string
+		want int64
+	}{
+		{"1,234 views", 1234},
+		{"1.2K views", 1200},
+		{"3.4M subscribers", 3_400_000},
+		{"1B views", 1_000_000_000},
+		{"50+ videos", 50},
+		{"No views", 0},
+		{"@handle • 2.5M subscribers", 2_500_000},
+
+		{"1.234 visualizações", 1234},
+		{"1,2 mil visualizações", 1200},
+		{"3,4 mi de visualizações", 3_400_000},
+		{"1,5 bi de visualizações", 1_500_000_000},
+		{"2,3 mi de inscritos", 2_300_000},
+
+		{"1.234.567 visualizaciones", 1_234_567},
+		{"1,2 mil visualizaciones", 1200},
+		{"3,4 M de visualizaciones", 3_400_000},
+		{"1,2 mil M de visualizaciones", 1_200_000_000},
+
+		{"1.234 Aufrufe", 1234},
+		{"1,2 Tsd. Aufrufe", 1200},
+		{"3,4 Mio. Abonnenten", 3_400_000},
+		{"1 Mrd. Aufrufe", 1_000_000_000},
+	}
+
+	for _, tt := range tests {
+		if got := ParseCount(tt.text); got != tt.want {
+			t.Errorf("ParseCount(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseRelativeTime(t *testing.T) {
+	tests := []struct {
+		text string
+		want time.Duration
+	}{
+		{"3 years ago", 3 * year},
+		{"Streamed 2 days ago", 2 * day},
+		{"1 month ago", month},
+
+		{"há 3 dias", 3 * day},
+		{"há 1 mês", month},
+		{"há 5 meses", 5 * month},
+		{"Transmitido há 2 semanas", 2 * week},
+
+		{"hace 1 año", year},
+		{"hace 4 horas", 4 * time.Hour},
+		{"Emitido hace 10 minutos", 10 * time.Minute},
+
+		{"vor 2 Tagen", 2 * day},
+		{"vor 1 Jahr", year},
+		{"vor 5 Monaten", 5 * month},
+		{"Live übertragen vor 30 Sekunden", 30 * time.Second},
+
+		{"1.2M views", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := ParseRelativeTime(tt.text); got != tt.want {
+			t.Errorf("ParseRelativeTime(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCountKind(t *testing.T) {
+	for _, text := range []string{"1.2M subscribers", "2,3 mi de inscritos", "1,2 M de suscriptores", "3,4 Mio. Abonnenten"} {
+		if !IsSubscriberCount(text) || IsViewCount(text) {
+			t.Errorf("%q not recognized as a subscriber count", text)
+		}
+	}
+	for _, text := range []string{"1.2M views", "1,2 mil visualizações", "1,2 mil visualizaciones", "1.234 Aufrufe"} {
+		if !IsViewCount(text) || IsSubscriberCount(text) {
+			t.Errorf("%q not recognized as a view count", text)
+		}
+	}
+	for _, text := range []string{"42 videos", "42 vídeos", "42 Videos"} {
+		if !IsVideoCount(text) {
+			t.Errorf("%q not recognized as a video count", text)
+		}
+	}
+}
@@ -52,31 +52,6 @@ func ParseDuration(dur string) int {
 	return hours*3600 + minutes*60 + seconds
 }
 
-func GetThumbnail(m map[string]any) string {
-	if thumbnails := DeepGet(m, "thumbnail", "thumbnails"); thumbnails != nil {
-		if thumbs, ok := thumbnails.([]any); ok && len(thumbs) > 0 {
-			lastThumb := thumbs[len(thumbs)-1]
-			if thumbMap, ok := lastThumb.(map[string]any); ok {
-				if url, ok := thumbMap["url"].(string); ok {
-					if strings.HasPrefix(url, "/") {
-						return "https:" + url
-					}
-					if strings.HasPrefix(url, "/") {
-						return "https://www.youtube.com" + url
-					}
-					return url
-				}
-			}
-		}
-	}
-
-	if videoID := Str(m["videoID"]); videoID != "" {
-		return "https://i.ytimg.com/vi/" + videoID + "/hqdefault.jpg"
-	}
-
-	return ""
-}
-
 func ExtractVideoID(link string) string {
 	patterns := []struct {
 		pattern string
@@ -96,6 +71,60 @@ func ExtractVideoID(link string) string {
 	return ""
 }
 
+// IsPlaylistURL reports whether link points at a playlist page rather than a single video
+func IsPlaylistURL(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil || !IsYouTubeURL(link) {
+		return false
+	}
+
+	// watch?v=...&list=... opens a video inside a playlist, keep treating it as a video
+	return strings.TrimSuffix(u.Path, "/") == "/playlist" && u.Query().Get("list") != ""
+}
+
+func ExtractPlaylistID(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("list")
+}
+
+// ChannelPath returns the canonical path ("/@handle", "/channel/UC...", "/c/name", "/user/name") of a channel
+// reference given as a URL, an @handle or a bare channel id
+func ChannelPath(ref string) string {
+	ref = strings.TrimSpace(ref)
+
+	switch {
+	case strings.HasPrefix(ref, "@"):
+		return "/" + ref
+	case strings.HasPrefix(ref, "UC") && !strings.Contains(ref, "/"):
+		return "/channel/" + ref
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil || !IsYouTubeURL(ref) {
+		return ""
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) == 0 || parts[0] == "" {
+		return ""
+	}
+
+	switch {
+	case strings.HasPrefix(parts[0], "@"):
+		return "/" + parts[0]
+	case (parts[0] == "channel" || parts[0] == "c" || parts[0] == "user") && len(parts) > 1:
+		return "/" + parts[0] + "/" + parts[1]
+	}
+	return ""
+}
+
+func IsChannelURL(link string) bool {
+	return IsYouTubeURL(link) && ChannelPath(link) != ""
+}
+
 func FormatDuration(seconds int) string {
 	if seconds == 0 {
 		return "LIVE"
@@ -115,3 +144,109 @@ func TruncateText(text string, maxLength int) string {
 	}
 	return text[:maxLength-3] + "..."
 }
+
+// ParseCount converts texts like "1,234 views", "1.2K", "3.4M subscribers", "1,2 mil visualizações"
+// or "1,2 Mio. Aufrufe" to a number
+func ParseCount(text string) int64 {
+	fields := strings.Fields(strings.ToLower(text))
+
+	for i, field := range fields {
+		if field[0] < '0' || field[0] > '9' {
+			continue
+		}
+
+		end := 0
+		for end < len(field) && (field[end] >= '0' && field[end] <= '9' || field[end] == '.' || field[end] == ',') {
+			end++
+		}
+		num := strings.TrimRight(field[:end], ".,")
+		// "50+ videos" on mixes
+		suffix := strings.Trim(field[end:], ".+")
+
+		// "1,2 mil" and "1,2 Mio." put the abbreviation in its own word, "1,2 mil M" even in two
+		if suffix == "" && i+1 < len(fields) {
+			next := strings.TrimSuffix(fields[i+1], ".")
+			if _, ok := countSuffixes[next]; ok {
+				suffix = next
+				if next == "mil" && i+2 < len(fields) && fields[i+2] == "m" {
+					suffix = "mil m"
+				}
+			}
+		}
+
+		multiplier, ok := countSuffixes[suffix]
+		if ok {
+			// abbreviated counts have at most a decimal separator, "1.2K" or "1,2 mil"
+			num = strings.ReplaceAll(num, ",", ".")
+		} else {
+			// full counts only have thousands separators, "1,234" or "1.234"
+			multiplier = 1
+			num = strings.NewReplacer(",", "", ".", "").Replace(num)
+		}
+
+		n, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0
+		}
+		return int64(n*multiplier + 0.5)
+	}
+
+	return 0
+}
+
+// FormatCount renders a number in the short form used by YouTube ("1.2K", "3.4M")
+func FormatCount(n int64) string {
+	switch {
+	case n >= 1_000_000_000:
+		return trimDecimal(float64(n)/1e9) + "B"
+	case n >= 1_000_000:
+		return trimDecimal(float64(n)/1e6) + "M"
+	case n >= 1_000:
+		return trimDecimal(float64(n)/1e3) + "K"
+	default:
+		return strconv.FormatInt(n, 10)
+	}
+}
+
+// FormatViews renders a view count as "1.2M views"
+func FormatViews(n int64) string {
+	if n == 1 {
+		return "1 view"
+	}
+	return FormatCount(n) + " views"
+}
+
+func trimDecimal(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 1, 64)
+	return strings.TrimSuffix(s, ".0")
+}
+
+// ParseQualityHeight extracts the resolution from labels like "1080p", "720p60" or "1440"
+func ParseQualityHeight(quality string) int {
+	quality = strings.ToLower(strings.TrimSpace(quality))
+	if quality == "hd" {
+		return 1080
+	}
+
+	end := 0
+	for end < len(quality) && quality[end] >= '0' && quality[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(quality[:end])
+	return n
+}
+
+// FormatBytes renders a byte count as "12.3 MB"
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	day   = 24 * time.Hour
+	week  = 7 * day
+	month = 30 * day
+	year  = 365 * day
+)
+
+// ParseRelativeTime turns YouTube's "3 years ago", "Streamed 2 days ago", "há 3 dias" or "vor 2 Tagen"
+// into an approximate age
+func ParseRelativeTime(text string) time.Duration {
+	fields := strings.Fields(strings.ToLower(text))
+	for i := 0; i+1 < len(fields); i++ {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			continue
+		}
+		for _, u := range relativeUnits {
+			for _, name := range u.names {
+				if strings.HasPrefix(fields[i+1], name) {
+					return time.Duration(n) * u.size
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// PublishedLabel prefers the age recomputed from the parsed duration and falls back to YouTube's own text
+func PublishedLabel(text string, age time.Duration) string {
+	if label := FormatRelativeTime(age); label != "" {
+		return label
+	}
+	return text
+}
+
+// FormatRelativeTime renders an age in the largest whole unit ("3 years ago", "1 day ago")
+func FormatRelativeTime(age time.Duration) string {
+	if age <= 0 {
+		return ""
+	}
+
+	for _, u := range relativeUnits {
+		if n := int(age / u.size); n >= 1 {
+			if n == 1 {
+				return "1 " + u.names[0] + " ago"
+			}
+			return strconv.Itoa(n) + " " + u.names[0] + "s ago"
+		}
+	}
+	return "just now"
+}
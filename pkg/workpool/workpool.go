@@ -0,0 +1,102 @@
+// Package workpool runs jobs on a fixed number of workers while keeping the load on any one host bounded.
+package workpool
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Options bounds a Run
+type Options struct {
+	Workers int // jobs running at once, at least 1
+	PerHost int // jobs running at once for the same host, 0 means Workers
+}
+
+// Job is one unit of work. Host names what the job talks to and is asked again each time the job is
+// considered, so a job follows its client to another host after a failover. Jobs with a nil Host, or one
+// that returns "", share one limit.
+type Job struct {
+	Host func() string
+	Run  func(ctx context.Context)
+}
+
+func (j Job) host() string {
+	if j.Host == nil {
+		return ""
+	}
+	return j.Host()
+}
+
+// Run works through jobs in order and returns once all of them finished or ctx is done, in which case the
+// jobs not started yet are dropped. A worker skips over jobs whose host is at its limit, so a busy host
+// does not hold up the others.
+func Run(ctx context.Context, opts Options, jobs []Job) {
+	workers := max(opts.Workers, 1)
+	perHost := opts.PerHost
+	if perHost <= 0 {
+		perHost = workers
+	}
+
+	q := &queue{pending: jobs, busy: map[string]int{}, perHost: perHost}
+	q.cond = sync.NewCond(&q.mu)
+	// wake the workers waiting for a host so they notice the cancellation
+	stop := context.AfterFunc(ctx, func() {
+		q.mu.Lock()
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	})
+	defer stop()
+
+	var wg sync.WaitGroup
+	for range min(workers, len(jobs)) {
+		wg.Go(func() {
+			for {
+				job, host, ok := q.next(ctx)
+				if !ok {
+					return
+				}
+				job.Run(ctx)
+				q.done(host)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+type queue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []Job
+	busy    map[string]int
+	perHost int
+}
+
+// next takes the first pending job whose host has room, waiting while none has. It returns the host the
+// job was counted against.
+func (q *queue) next(ctx context.Context) (Job, string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		if ctx.Err() != nil || len(q.pending) == 0 {
+			return Job{}, "", false
+		}
+		for i, job := range q.pending {
+			if host := job.host(); q.busy[host] < q.perHost {
+				// a copy, the caller's slice stays as it was
+				q.pending = slices.Concat(q.pending[:i], q.pending[i+1:])
+				q.busy[host]++
+				return job, host, true
+			}
+		}
+		q.cond.Wait()
+	}
+}
+
+func (q *queue) done(host string) {
+	q.mu.Lock()
+	q.busy[host]--
+	q.cond.Broadcast()
+	q.mu.Unlock()
+}
@@ -0,0 +1,160 @@
+package workpool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gauge tracks how many jobs run at once, overall and per host
+type gauge struct {
+	mu      sync.Mutex
+	running map[string]int
+	total   int
+	peak    map[string]int
+	peakAll int
+}
+
+func (g *gauge) enter(host string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.running[host]++
+	g.total++
+	g.peak[host] = max(g.peak[host], g.running[host])
+	g.peakAll = max(g.peakAll, g.total)
+}
+
+func (g *gauge) leave(host string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.running[host]--
+	g.total--
+}
+
+func fixed(host string) func() string {
+	return func() string { return host }
+}
+
+func TestRunLimits(t *testing.T) {
+	g := &gauge{running: map[string]int{}, peak: map[string]int{}}
+	var ran atomic.Int32
+
+	var jobs []Job
+	for i := range 30 {
+		host := "a.example"
+		if i%3 == 0 {
+			host = "b.example"
+		}
+		jobs = append(jobs, Job{Host: fixed(host), Run: func(ctx context.Context) {
+			g.enter(host)
+			time.Sleep(5 * time.Millisecond)
+			g.leave(host)
+			ran.Add(1)
+		}})
+	}
+
+	Run(t.Context(), Options{Workers: 4, PerHost: 2}, jobs)
+
+	if ran.Load() != 30 {
+		t.Fatalf("%d of 30 jobs ran", ran.Load())
+	}
+	if g.peakAll > 4 {
+		t.Errorf("%d jobs ran at once, want at most 4", g.peakAll)
+	}
+	for host, peak := range g.peak {
+		if peak > 2 {
+			t.Errorf("%d jobs ran at once for %s, want at most 2", peak, host)
+		}
+	}
+	if g.peakAll < 3 {
+		t.Errorf("only %d jobs ran at once, a full host should not hold up the other", g.peakAll)
+	}
+}
+
+func TestRunBusyHostDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	other := make(chan struct{})
+
+	jobs := []Job{
+		{Host: fixed("slow.example"), Run: func(ctx context.Context) { <-release }},
+		{Host: fixed("slow.example"), Run: func(ctx context.Context) {}},
+		{Host: fixed("fast.example"), Run: func(ctx context.Context) { close(other) }},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		Run(t.Context(), Options{Workers: 2, PerHost: 1}, jobs)
+		close(done)
+	}()
+
+	select {
+	case <-other:
+	case <-time.After(time.Second):
+		t.Fatal("job for another host waited behind the busy one")
+	}
+	close(release)
+	<-done
+}
+
+func TestRunHostResolvedWhenPicked(t *testing.T) {
+	var current atomic.Value
+	current.Store("old.example")
+	host := func() string { return current.Load().(string) }
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	jobs := []Job{
+		// fails over while it runs, the job behind it must go to the new host right away
+		{Host: host, Run: func(ctx context.Context) {
+			current.Store("new.example")
+			close(started)
+			<-release
+		}},
+		{Host: host, Run: func(ctx context.Context) { close(release) }},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		Run(t.Context(), Options{Workers: 2, PerHost: 1}, jobs)
+		close(done)
+	}()
+
+	<-started
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job waited for the host its client left")
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	var ran atomic.Int32
+
+	var jobs []Job
+	for range 10 {
+		jobs = append(jobs, Job{Run: func(ctx context.Context) {
+			ran.Add(1)
+			cancel()
+			<-ctx.Done()
+		}})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, Options{Workers: 2}, jobs)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+	if n := ran.Load(); n > 2 {
+		t.Errorf("%d jobs started after the cancellation", n-2)
+	}
+}